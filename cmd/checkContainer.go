@@ -11,18 +11,7 @@ import (
 func init() {
 	rootCmd.AddCommand(checkCmd)
 
-	subcommands := []*cobra.Command{
-		checkContainerCECmd, checkContainerCommerceCmd, checkContainerDXPCmd,
-		checkContainerNightlyCmd, checkContainerReleaseCmd}
-
-	for i := 0; i < len(subcommands); i++ {
-		subcommand := subcommands[i]
-
-		subcommand.PersistentFlags().BoolVarP(&verbose, "verbose", "V", false, "Runs commands with Debug log level")
-		subcommand.VisitParents(addVerboseFlag)
-
-		checkCmd.AddCommand(subcommand)
-	}
+	addPortalSubcommands(checkCmd, newCheckContainerCmd)
 }
 
 var checkCmd = &cobra.Command{
@@ -36,72 +25,42 @@ var checkCmd = &cobra.Command{
 	Run: func(cmd *cobra.Command, args []string) {
 		SubCommandInfo()
 	},
+	Annotations: map[string]string{containersAnnotation: containersAll},
 }
 
-var checkContainerCECmd = &cobra.Command{
-	Use:   "ce",
-	Short: "Checks if there is a CE container created by lpn",
-	Long: `Checks if there is a CE container created by lpn (Liferay Portal Nook).
-	Uses docker container inspect to check if there is a CE container with name [lpn-release] created by lpn (Liferay Portal Nook)`,
-	Run: func(cmd *cobra.Command, args []string) {
-		ce := liferay.CE{}
-
-		checkDockerContainerExists(ce)
-	},
-}
-
-var checkContainerCommerceCmd = &cobra.Command{
-	Use:   "commerce",
-	Short: "Checks if there is a Commerce container created by lpn",
-	Long: `Checks if there is a Commerce container created by lpn (Liferay Portal Nook).
-	Uses docker container inspect to check if there is a Commerce container with name [lpn-commerce] created by lpn (Liferay Portal Nook)`,
-	Run: func(cmd *cobra.Command, args []string) {
-		commerce := liferay.Commerce{}
-
-		checkDockerContainerExists(commerce)
-	},
-}
-
-var checkContainerDXPCmd = &cobra.Command{
-	Use:   "dxp",
-	Short: "Checks if there is a DXP container created by lpn",
-	Long: `Checks if there is a DXP container created by lpn (Liferay Portal Nook).
-	Uses docker container inspect to check if there is a DXP container with name [lpn-release] created by lpn (Liferay Portal Nook)`,
-	Run: func(cmd *cobra.Command, args []string) {
-		dxp := liferay.DXP{}
-
-		checkDockerContainerExists(dxp)
-	},
-}
-
-var checkContainerNightlyCmd = &cobra.Command{
-	Use:   "nightly",
-	Short: "Checks if there is a Nightly Build container created by lpn",
-	Long: `Checks if there is a Nightly Build container created by lpn (Liferay Portal Nook).
-	Uses docker container inspect to check if there is a Nightly Build container with name [lpn-nightly] created by lpn (Liferay Portal Nook)`,
-	Run: func(cmd *cobra.Command, args []string) {
-		nightly := liferay.Nightly{}
-
-		checkDockerContainerExists(nightly)
-	},
-}
-
-var checkContainerReleaseCmd = &cobra.Command{
-	Use:   "release",
-	Short: "Checks if there is a Release container created by lpn",
-	Long: `Checks if there is a Release container created by lpn (Liferay Portal Nook).
-	Uses docker container inspect to check if there is a Release container with name [lpn-release] created by lpn (Liferay Portal Nook)`,
-	Run: func(cmd *cobra.Command, args []string) {
-		release := liferay.Release{}
-
-		checkDockerContainerExists(release)
-	},
+// newCheckContainerCmd returns the subcommand checking the container of a portal image type
+func newCheckContainerCmd(image liferay.Image) *cobra.Command {
+	return &cobra.Command{
+		Short: "Checks if there is a " + image.GetDescription() + " container created by lpn",
+		Long: `Checks if there is a ` + image.GetDescription() + ` container created by lpn (Liferay Portal Nook).
+	Uses docker container inspect to check if there is a container with name [` + image.GetContainerName() + `] created by lpn (Liferay Portal Nook)`,
+		Run: func(cmd *cobra.Command, args []string) {
+			checkDockerContainerExists(image)
+		},
+	}
 }
 
 // checkDockerContainerExists removes the running container
 func checkDockerContainerExists(image liferay.Image) {
 	exists := docker.CheckDockerContainerExists(image.GetContainerName())
 
+	if isStructuredOutput() {
+		result := CheckContainerOutput{
+			Container: image.GetContainerName(),
+			Exists:    exists,
+		}
+
+		if exists {
+			instance, err := docker.GetContainerInstance(image.GetContainerName())
+			if err == nil {
+				result.Instance = &instance
+			}
+		}
+
+		printStructuredOutput(result)
+		return
+	}
+
 	if !exists {
 		log.WithFields(log.Fields{
 			"container": image.GetContainerName(),
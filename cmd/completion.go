@@ -0,0 +1,397 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"strings"
+
+	docker "github.com/mdelapenya/lpn/docker"
+	internal "github.com/mdelapenya/lpn/internal"
+	registry "github.com/mdelapenya/lpn/registry"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// completeCommand name of the hidden command the completion scripts call to get the candidates
+const completeCommand = "__complete"
+
+// containersAnnotation annotation of the commands acting on the containers of the image types, whose
+// type subcommands are completed with the types of the lpn containers. The value tells if only the
+// running containers are taken into account
+const containersAnnotation = "lpn-containers"
+
+const (
+	containersAll     = "all"
+	containersRunning = "running"
+)
+
+func init() {
+	rootCmd.AddCommand(completionCmd)
+	rootCmd.AddCommand(completeCmd)
+}
+
+var completionCmd = &cobra.Command{
+	Use:   "completion bash|zsh|fish",
+	Short: "Generates the shell completion script",
+	Long: `Generates the completion script of lpn for a shell: bash, zsh or fish.
+	Besides the commands and flags, the script completes the tags of the tag flag from the local images
+	and the cached listings of the registry, the datastores of the datastore flag, and the image types
+	of the commands acting on containers from the lpn containers.`,
+	Example: `  source <(lpn completion bash)
+  lpn completion zsh > "${fpath[1]}/_lpn"
+  lpn completion fish > ~/.config/fish/completions/lpn.fish`,
+	ValidArgs: []string{"bash", "zsh", "fish"},
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) != 1 {
+			return errors.New("completion requires the shell to generate the script for: bash, zsh or fish")
+		}
+
+		return cobra.OnlyValidArgs(cmd, args)
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		switch args[0] {
+		case "bash":
+			fmt.Print(bashCompletion)
+		case "zsh":
+			fmt.Print(zshCompletion)
+		case "fish":
+			fmt.Print(fishCompletion)
+		}
+	},
+}
+
+var completeCmd = &cobra.Command{
+	Use:                completeCommand + " [words]",
+	Short:              "Prints the completion candidates of the last word of a command line",
+	Hidden:             true,
+	DisableFlagParsing: true,
+	Run: func(cmd *cobra.Command, args []string) {
+		// the logs would be taken as candidates
+		log.SetOutput(ioutil.Discard)
+
+		for _, candidate := range complete(args) {
+			fmt.Println(candidate)
+		}
+	},
+}
+
+// complete returns the candidates for the last word of the arguments of a command line, which is
+// empty when completing a new word. No candidates means that the shell should complete files
+func complete(args []string) []string {
+	if len(args) == 0 {
+		return nil
+	}
+
+	toComplete := args[len(args)-1]
+	previous := args[:len(args)-1]
+
+	cmd, _, err := rootCmd.Find(previous)
+	if err != nil {
+		return nil
+	}
+
+	if strings.HasPrefix(toComplete, "--") && strings.Contains(toComplete, "=") {
+		kv := strings.SplitN(toComplete, "=", 2)
+
+		flag := lookupFlag(cmd, kv[0])
+		if flag == nil {
+			return nil
+		}
+
+		candidates := []string{}
+		for _, value := range completeFlagValue(cmd, flag, kv[1]) {
+			candidates = append(candidates, kv[0]+"="+value)
+		}
+
+		return candidates
+	}
+
+	if len(previous) > 0 {
+		last := previous[len(previous)-1]
+
+		if strings.HasPrefix(last, "-") && !strings.Contains(last, "=") {
+			flag := lookupFlag(cmd, last)
+			if flag != nil && flag.NoOptDefVal == "" {
+				return completeFlagValue(cmd, flag, toComplete)
+			}
+		}
+	}
+
+	if strings.HasPrefix(toComplete, "-") {
+		return completeFlags(cmd, toComplete)
+	}
+
+	if cmd == cpCmd {
+		return completeContainerPaths(toComplete)
+	}
+
+	if cmd.Parent() == configCmd && cmd.Name() == previous[len(previous)-1] {
+		return completeConfigKeys(cmd, toComplete)
+	}
+
+	return completeSubcommands(cmd, toComplete)
+}
+
+// completeContainerPaths returns the <type>: prefixes of the running portal containers, for the
+// arguments of the cp command. The words looking like local paths are left to the shell
+func completeContainerPaths(toComplete string) []string {
+	if strings.ContainsAny(toComplete, ":/.~") {
+		return nil
+	}
+
+	containerTypes, err := docker.GetContainerTypes(true)
+	if err != nil {
+		return nil
+	}
+
+	candidates := []string{}
+	for _, t := range containerTypes {
+		candidates = append(candidates, t+":")
+	}
+
+	return filterCandidates(candidates, toComplete)
+}
+
+// completeConfigKeys returns the keys of the effective configuration, for the first argument of the
+// config subcommands taking a key
+func completeConfigKeys(cmd *cobra.Command, toComplete string) []string {
+	if cmd != configGetCmd && cmd != configSetCmd && cmd != configUnsetCmd && cmd != configResetCmd {
+		return nil
+	}
+
+	values, err := internal.GetConfigValues()
+	if err != nil {
+		return nil
+	}
+
+	candidates := []string{}
+	for key := range values {
+		candidates = append(candidates, key)
+	}
+
+	sort.Strings(candidates)
+
+	return filterCandidates(candidates, toComplete)
+}
+
+// completeFlags returns the long names of the flags of a command, including the inherited ones
+func completeFlags(cmd *cobra.Command, toComplete string) []string {
+	candidates := []string{}
+
+	addFlag := func(flag *pflag.Flag) {
+		if !flag.Hidden {
+			candidates = append(candidates, "--"+flag.Name)
+		}
+	}
+
+	cmd.NonInheritedFlags().VisitAll(addFlag)
+	cmd.InheritedFlags().VisitAll(addFlag)
+
+	sort.Strings(candidates)
+
+	return filterCandidates(candidates, toComplete)
+}
+
+// completeFlagValue returns the values of the flags with dynamic values: the tags of the image
+// type, present in the local images or in the cached listings of the registry, the datastores and
+// the profiles
+func completeFlagValue(cmd *cobra.Command, flag *pflag.Flag, toComplete string) []string {
+	switch flag.Name {
+	case "datastore":
+		return filterCandidates(docker.GetDatastores(), toComplete)
+	case "output":
+		return filterCandidates([]string{outputJSON, outputTable, outputYAML}, toComplete)
+	case "profile":
+		profiles, err := internal.GetProfiles()
+		if err != nil {
+			return nil
+		}
+
+		return filterCandidates(profiles, toComplete)
+	case "tag":
+		t := getCompletionType(cmd)
+		if t == "" {
+			return nil
+		}
+
+		return filterCandidates(getCompletionTags(t), toComplete)
+	}
+
+	return nil
+}
+
+// completeSubcommands returns the valid arguments and subcommands of a command. The subcommands of the commands acting
+// on containers are restricted to the image types with a container, if any
+func completeSubcommands(cmd *cobra.Command, toComplete string) []string {
+	candidates := append([]string{}, cmd.ValidArgs...)
+	for _, subcommand := range cmd.Commands() {
+		if subcommand.IsAvailableCommand() {
+			candidates = append(candidates, subcommand.Name())
+		}
+	}
+
+	if value, ok := cmd.Annotations[containersAnnotation]; ok {
+		containerTypes, err := docker.GetContainerTypes(value == containersRunning)
+		if err == nil && len(containerTypes) > 0 {
+			candidates = containerTypes
+		}
+	}
+
+	return filterCandidates(candidates, toComplete)
+}
+
+// filterCandidates returns the candidates starting with the word being completed, without duplicates
+func filterCandidates(candidates []string, toComplete string) []string {
+	filtered := []string{}
+	seen := map[string]bool{}
+
+	for _, candidate := range candidates {
+		if strings.HasPrefix(candidate, toComplete) && !seen[candidate] {
+			seen[candidate] = true
+			filtered = append(filtered, candidate)
+		}
+	}
+
+	return filtered
+}
+
+// getCompletionTags returns the tags of an image type from the local images, followed by the ones
+// of the cached listing of the registry. The registry is not reached, to complete quickly
+func getCompletionTags(t string) []string {
+	image := newImage(t, "")
+
+	tags, err := docker.GetLocalTags(image)
+	if err != nil {
+		tags = []string{}
+	}
+
+	sort.Strings(tags)
+
+	if cached, ok := registry.GetCachedTagSource(image, false).(registry.CachedTagSource); ok {
+		cachedTags, err := cached.GetCachedTags()
+		if err == nil {
+			for _, tag := range cachedTags {
+				tags = append(tags, tag.Name)
+			}
+		}
+	}
+
+	return tags
+}
+
+// getCompletionType returns the image type of a command, which is the name of the command or of
+// one of its parents, or empty if it does not belong to a type
+func getCompletionType(cmd *cobra.Command) string {
+	for c := cmd; c != nil; c = c.Parent() {
+		if internal.LpnConfig.IsPortalType(c.Name()) {
+			return c.Name()
+		}
+	}
+
+	return ""
+}
+
+// lookupFlag returns the flag of a command, or an inherited one, by its long name, as --tag, or by
+// its shorthand, as -t
+func lookupFlag(cmd *cobra.Command, name string) *pflag.Flag {
+	flags := []*pflag.FlagSet{cmd.NonInheritedFlags(), cmd.InheritedFlags()}
+
+	for _, flagSet := range flags {
+		var flag *pflag.Flag
+		if strings.HasPrefix(name, "--") {
+			flag = flagSet.Lookup(name[2:])
+		} else if len(name) == 2 {
+			flag = flagSet.ShorthandLookup(name[1:])
+		}
+
+		if flag != nil {
+			return flag
+		}
+	}
+
+	return nil
+}
+
+const bashCompletion = `# bash completion for lpn
+#
+# Load it in the current shell with: source <(lpn completion bash)
+
+_lpn_completions() {
+    local line="${COMP_LINE:0:COMP_POINT}"
+    local -a words
+    read -r -a words <<< "${line}"
+
+    # a new word is being completed
+    if [[ "${line}" =~ [[:space:]]$ ]]; then
+        words+=("")
+    fi
+
+    local cur="${words[${#words[@]}-1]}"
+
+    local IFS=$'\n'
+    local -a candidates
+    candidates=($(lpn ` + completeCommand + ` "${words[@]:1}" 2>/dev/null))
+
+    # bash replaces only the part of the word after the last = or :
+    local breaks="${cur%"${cur##*[=:]}"}"
+    COMPREPLY=("${candidates[@]#"${breaks}"}")
+
+    if [[ ${#COMPREPLY[@]} -eq 1 && "${COMPREPLY[0]}" == *: ]]; then
+        compopt -o nospace
+    fi
+}
+
+complete -o default -F _lpn_completions lpn
+`
+
+const zshCompletion = `#compdef lpn
+#
+# Load it in the current shell with: source <(lpn completion zsh)
+
+_lpn() {
+  local -a candidates suffixed
+  candidates=(${(f)"$(lpn ` + completeCommand + ` "${(@)words[2,CURRENT]}" 2>/dev/null)"})
+
+  if (( ${#candidates} == 0 )); then
+    _files
+    return
+  fi
+
+  # the container paths of cp are continued after the colon
+  suffixed=(${(M)candidates:#*:})
+  candidates=(${candidates:#*:})
+
+  (( ${#suffixed} )) && compadd -S '' -- "${suffixed[@]}"
+  (( ${#candidates} )) && compadd -- "${candidates[@]}"
+}
+
+if [ "$funcstack[1]" = "_lpn" ]; then
+  _lpn "$@"
+else
+  compdef _lpn lpn
+fi
+`
+
+const fishCompletion = `# fish completion for lpn
+#
+# Load it in the current shell with: lpn completion fish | source
+
+function __lpn_complete
+    set -l words (commandline -opc)
+    set -e words[1]
+    set -l current (commandline -ct)
+    lpn ` + completeCommand + ` $words "$current" 2>/dev/null
+end
+
+function __lpn_has_candidates
+    set -l candidates (__lpn_complete)
+    test (count $candidates) -gt 0
+end
+
+complete -c lpn -f -n '__lpn_has_candidates' -a '(__lpn_complete)'
+complete -c lpn -F -n 'not __lpn_has_candidates'
+`
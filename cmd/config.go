@@ -0,0 +1,342 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"runtime"
+	"sort"
+	"strings"
+
+	internal "github.com/mdelapenya/lpn/internal"
+	tablewriter "github.com/olekukonko/tablewriter"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	rootCmd.AddCommand(configCmd)
+
+	configCmd.AddCommand(
+		configListCmd, configGetCmd, configSetCmd, configUnsetCmd, configResetCmd, configPathCmd,
+		configEditCmd)
+}
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Views and edits the configuration of lpn",
+	Long: `Views and edits the configuration file of lpn, located in the workspace of lpn (~/.lpn/config.yaml).
+	The keys are separated by dots, as images.portal.ce.tag. The values are validated before writing
+	them, so that a mistake does not leave the configuration broken.`,
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		enableDebugLevel()
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		cmd.Help()
+	},
+}
+
+var configListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "Lists the effective configuration",
+	Long: `Lists the effective configuration, which includes the built-in values of the keys not present in
+	the configuration file.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		values, err := internal.GetConfigValues()
+		if err != nil {
+			log.WithFields(log.Fields{
+				"error": err,
+			}).Fatal("Could not read the configuration")
+		}
+
+		// the credentials of the tag sources are shown with the get command
+		for key := range values {
+			if strings.HasSuffix(key, ".password") || strings.HasSuffix(key, ".token") {
+				values[key] = maskedValue
+			}
+		}
+
+		printConfigValues(values)
+	},
+}
+
+var configGetCmd = &cobra.Command{
+	Use:   "get KEY",
+	Short: "Shows the effective value of a key of the configuration",
+	Long: `Shows the effective value of a key of the configuration. If the key contains other keys, as
+	images.portal.ce, all of them are shown.`,
+	Example: `  lpn config get images.portal.ce.tag
+  lpn config get images.portal.ce`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		values, err := internal.GetConfigValue(args[0])
+		if err != nil {
+			log.WithFields(log.Fields{
+				"key":   args[0],
+				"error": err,
+			}).Fatal("Could not read the key of the configuration")
+		}
+
+		// a single value is printed as is, to be used by scripts
+		if len(values) == 1 && !isStructuredOutput() {
+			for key, value := range values {
+				if strings.EqualFold(key, args[0]) {
+					fmt.Println(value)
+					return
+				}
+			}
+		}
+
+		printConfigValues(values)
+	},
+}
+
+var configSetCmd = &cobra.Command{
+	Use:   "set KEY VALUE",
+	Short: "Sets the value of a key of the configuration",
+	Long: `Sets the value of a key of the configuration file, after validating it against the known keys:
+	the database types, the portal types, the image names, tags, durations and memory limits. New
+	portal types are added setting their image and tag.`,
+	Example: `  lpn config set images.portal.ce.tag 7.2.0-ga1
+  lpn config set resources.memLimit 4g
+  lpn config set images.portal.mine.image myorg/portal
+  lpn config set images.portal.mine.tag latest`,
+	Args: cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		err := internal.SetConfigValue(args[0], args[1])
+		if err != nil {
+			log.WithFields(log.Fields{
+				"key":   args[0],
+				"value": args[1],
+				"error": err,
+			}).Fatal("Could not set the key of the configuration")
+		}
+
+		log.WithFields(log.Fields{
+			"key":   args[0],
+			"value": args[1],
+		}).Info("Configuration updated")
+	},
+}
+
+var configUnsetCmd = &cobra.Command{
+	Use:   "unset KEY",
+	Short: "Removes a key from the configuration",
+	Long: `Removes a key from the configuration file. The built-in value of the key, if any, is used from
+	then on. If the key contains other keys, as images.portal.mine, all of them are removed.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		err := internal.UnsetConfigValue(args[0])
+		if err != nil {
+			log.WithFields(log.Fields{
+				"key":   args[0],
+				"error": err,
+			}).Fatal("Could not remove the key of the configuration")
+		}
+
+		log.WithFields(log.Fields{
+			"key": args[0],
+		}).Info("Key removed from the configuration")
+	},
+}
+
+var configResetCmd = &cobra.Command{
+	Use:   "reset [KEY]",
+	Short: "Resets the configuration to the built-in values",
+	Long: `Resets a key of the configuration to its built-in value, or removes it if it has no built-in value.
+	Without key, the whole configuration file is written with the built-in values, after backing it up
+	next to it.`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		key := ""
+		if len(args) == 1 {
+			key = args[0]
+		}
+
+		err := internal.ResetConfig(key)
+		if err != nil {
+			log.WithFields(log.Fields{
+				"key":   key,
+				"error": err,
+			}).Fatal("Could not reset the configuration")
+		}
+
+		log.WithFields(log.Fields{
+			"key": key,
+		}).Info("Configuration reset")
+	},
+}
+
+var configPathCmd = &cobra.Command{
+	Use:   "path",
+	Short: "Shows the path of the configuration file",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		fmt.Println(internal.GetConfigFile())
+	},
+}
+
+var configEditCmd = &cobra.Command{
+	Use:   "edit",
+	Short: "Edits the configuration file",
+	Long: `Opens a copy of the configuration file with the editor defined by the VISUAL or EDITOR environment
+	variables, defaulting to vi (notepad on Windows). The configuration file is replaced by the copy
+	only if it is valid. Otherwise, the errors are listed and the copy is kept to fix them.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		editConfig()
+	},
+}
+
+// checkConfig validates the configuration file before running a command, so that a mistake is not
+// silently used. The config command is not checked, so that it can fix the file, nor the completion
+func checkConfig(args []string) {
+	cmd, _, err := rootCmd.Find(args)
+	if err == nil {
+		for c := cmd; c != nil; c = c.Parent() {
+			if c == configCmd || c == completionCmd || c == completeCmd {
+				return
+			}
+		}
+	}
+
+	errs := internal.ValidateConfig()
+	if len(errs) == 0 {
+		return
+	}
+
+	for _, e := range errs {
+		log.WithFields(log.Fields{
+			"error": e,
+		}).Error("Invalid configuration")
+	}
+
+	log.WithFields(log.Fields{
+		"configFile": internal.GetConfigFile(),
+	}).Fatal("The configuration file is not valid. Please fix the keys above with 'lpn config edit', or reset them with 'lpn config reset KEY'")
+}
+
+// editConfig opens a copy of the configuration file with the editor of the user, replacing the
+// configuration file if the copy is valid
+func editConfig() {
+	configFile := internal.GetConfigFile()
+
+	content, err := ioutil.ReadFile(configFile)
+	if err != nil {
+		log.WithFields(log.Fields{
+			"configFile": configFile,
+			"error":      err,
+		}).Fatal("Could not read the configuration file")
+	}
+
+	tmpFile, err := ioutil.TempFile("", "lpn-config-*.yaml")
+	if err == nil {
+		_, err = tmpFile.Write(content)
+		tmpFile.Close()
+	}
+	if err != nil {
+		log.WithFields(log.Fields{
+			"error": err,
+		}).Fatal("Could not create the copy of the configuration file")
+	}
+
+	err = runEditor(tmpFile.Name())
+	if err != nil {
+		log.WithFields(log.Fields{
+			"file":  tmpFile.Name(),
+			"error": err,
+		}).Fatal("Could not run the editor. Please set the VISUAL or EDITOR environment variables")
+	}
+
+	edited, err := ioutil.ReadFile(tmpFile.Name())
+	if err != nil {
+		log.WithFields(log.Fields{
+			"file":  tmpFile.Name(),
+			"error": err,
+		}).Fatal("Could not read the edited configuration file")
+	}
+
+	if string(edited) == string(content) {
+		os.Remove(tmpFile.Name())
+		log.Info("The configuration file has not changed")
+		return
+	}
+
+	errs := internal.ReplaceConfigFile(edited)
+	if len(errs) > 0 {
+		messages := []string{}
+		for _, e := range errs {
+			messages = append(messages, e.Error())
+		}
+
+		log.WithFields(log.Fields{
+			"file":   tmpFile.Name(),
+			"errors": strings.Join(messages, "; "),
+		}).Fatal("The edited configuration file is not valid, so it has not been saved. Please fix the copy and copy it over the configuration file, or edit it again")
+	}
+
+	os.Remove(tmpFile.Name())
+
+	log.WithFields(log.Fields{
+		"configFile": configFile,
+	}).Info("Configuration file updated")
+}
+
+// printConfigValues prints the keys of the configuration, sorted, as a table or as a structured
+// document
+func printConfigValues(values map[string]interface{}) {
+	if isStructuredOutput() {
+		printStructuredOutput(values)
+		return
+	}
+
+	keys := []string{}
+	for key := range values {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"Key", "Value"})
+	table.SetAlignment(tablewriter.ALIGN_LEFT)
+	table.SetAutoWrapText(false)
+
+	for _, key := range keys {
+		table.Append([]string{key, fmt.Sprint(values[key])})
+	}
+
+	table.Render()
+}
+
+// runEditor opens a file with the editor of the user, waiting for it to be closed
+func runEditor(file string) error {
+	editor := os.Getenv("VISUAL")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+
+	if editor == "" {
+		editor = "vi"
+		if runtime.GOOS == "windows" {
+			editor = "notepad"
+		}
+	}
+
+	// the editor can include arguments, as "code --wait"
+	fields := strings.Fields(editor)
+	if len(fields) == 0 {
+		return errors.New("the editor is empty")
+	}
+
+	command := exec.Command(fields[0], append(fields[1:], file)...)
+	command.Stdin = os.Stdin
+	command.Stdout = os.Stdout
+	command.Stderr = os.Stderr
+
+	return command.Run()
+}
@@ -0,0 +1,129 @@
+package cmd
+
+import (
+	"errors"
+	"strings"
+
+	docker "github.com/mdelapenya/lpn/docker"
+	internal "github.com/mdelapenya/lpn/internal"
+	liferay "github.com/mdelapenya/lpn/liferay"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	rootCmd.AddCommand(cpCmd)
+}
+
+var cpCmd = &cobra.Command{
+	Use:   "cp <type>:<path> <local> | <local> <type>:<path>",
+	Short: "Copies files between a Liferay Portal instance and the local filesystem",
+	Long: `Copies files or directories, recursively, between the container of a Liferay Portal instance and
+	the local filesystem, in any direction. The container side is identified by the image type followed by
+	a colon and a path, which is resolved against Liferay Home if it's relative.
+	The files copied to the container are owned by the user running the portal.`,
+	Example: `  lpn cp ce:portal-ext.properties .
+  lpn cp dxp:osgi/state /tmp/state
+  lpn cp ./my-module.jar ce:osgi/modules`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) != 2 {
+			return errors.New("cp requires two arguments representing the source and the destination")
+		}
+
+		_, _, sourceInContainer := parseContainerPath(args[0])
+		_, _, destinationInContainer := parseContainerPath(args[1])
+
+		if sourceInContainer == destinationInContainer {
+			return errors.New("cp requires one, and only one, of the arguments to be a <type>:<path> of a container")
+		}
+
+		return nil
+	},
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		enableDebugLevel()
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		copyFiles(args[0], args[1])
+	},
+}
+
+// copyFiles copies from the source to the destination, one of them being a path of a container
+func copyFiles(source string, destination string) {
+	if t, containerPath, ok := parseContainerPath(source); ok {
+		image := getContainerImage(t)
+		containerPath = liferay.GetContainerPath(image, containerPath)
+
+		err := docker.CopyFromContainer(image, containerPath, destination)
+		if err != nil {
+			log.WithFields(log.Fields{
+				"container": image.GetContainerName(),
+				"from":      containerPath,
+				"to":        destination,
+				"error":     err,
+			}).Fatal("Impossible to copy from the container")
+		}
+
+		log.WithFields(log.Fields{
+			"container": image.GetContainerName(),
+			"from":      containerPath,
+			"to":        destination,
+		}).Info("Copied from the container")
+
+		return
+	}
+
+	t, containerPath, _ := parseContainerPath(destination)
+	image := getContainerImage(t)
+	containerPath = liferay.GetContainerPath(image, containerPath)
+
+	err := docker.CopyToContainer(image, source, containerPath)
+	if err != nil {
+		log.WithFields(log.Fields{
+			"container": image.GetContainerName(),
+			"from":      source,
+			"to":        containerPath,
+			"error":     err,
+		}).Fatal("Impossible to copy to the container")
+	}
+
+	log.WithFields(log.Fields{
+		"container": image.GetContainerName(),
+		"from":      source,
+		"to":        containerPath,
+	}).Info("Copied to the container")
+}
+
+// getContainerImage returns the image of the container of a portal image type, which must exist
+func getContainerImage(t string) liferay.Image {
+	image := newImage(t, "")
+
+	if !docker.CheckDockerContainerExists(image.GetContainerName()) {
+		log.WithFields(log.Fields{
+			"container": image.GetContainerName(),
+		}).Fatal("The container does not exist")
+	}
+
+	return newImage(t, getTag(image))
+}
+
+// parseContainerPath splits an argument in the <type>:<path> form into the image type and the
+// path, returning false if the argument is a local path
+func parseContainerPath(arg string) (string, string, bool) {
+	index := strings.Index(arg, ":")
+	if index <= 0 {
+		return "", "", false
+	}
+
+	t := arg[:index]
+	if !internal.LpnConfig.IsPortalType(t) {
+		return "", "", false
+	}
+
+	containerPath := arg[index+1:]
+	if containerPath == "" {
+		containerPath = "."
+	}
+
+	return t, containerPath, true
+}
@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"time"
+
+	date "github.com/mdelapenya/lpn/date"
+	docker "github.com/mdelapenya/lpn/docker"
+	internal "github.com/mdelapenya/lpn/internal"
+	liferay "github.com/mdelapenya/lpn/liferay"
+	registry "github.com/mdelapenya/lpn/registry"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var tagDate string
+
+// addDateFlag adds the flag to select an image tagged by date, as nightly builds
+func addDateFlag(subcommand *cobra.Command) {
+	subcommand.Flags().StringVar(
+		&tagDate, "date", "", "Sets the date of the image tag, in the YYYY-MM-dd format. It cannot be combined with the tag flag")
+}
+
+// getDateTag returns the tag of an image tagged by date. If the date flag is set, it is converted
+// into a tag; if a tag is set, it is returned as is; and if not, the tag of the most recent date
+// present in the local images, or in the registry if remote is true, is returned
+func getDateTag(image liferay.Image, tag string, remote bool) string {
+	if tagDate != "" {
+		if tag != "" {
+			log.WithFields(log.Fields{
+				"date": tagDate,
+				"tag":  tag,
+			}).Fatal("Please use the date or the tag flag, but not both")
+		}
+
+		dateTag, err := date.ParseTag(tagDate)
+		if err != nil {
+			log.WithFields(log.Fields{
+				"date":  tagDate,
+				"error": err,
+			}).Fatal("The date is not valid")
+		}
+
+		return dateTag
+	}
+
+	if tag != "" {
+		return tag
+	}
+
+	return findDateTag(image, remote)
+}
+
+// findDateTag looks back for the most recent date tag of the image, first in the local images and
+// then in the registry, falling back to the current date if none is found
+func findDateTag(image liferay.Image, remote bool) string {
+	days := internal.LpnConfig.GetDateTagsLookbackDays()
+	candidates := date.LastTags(time.Now(), days)
+
+	remoteTags := map[string]bool{}
+	if remote {
+		tags, err := registry.GetAllTags(registry.GetCachedTagSource(image, false), 100)
+		if err != nil {
+			log.WithFields(log.Fields{
+				"repository": image.GetRepository(),
+				"error":      err,
+			}).Warn("Could not read the tags from the registry. Only local images will be checked")
+		}
+
+		for _, t := range tags {
+			remoteTags[t.Name] = true
+		}
+	}
+
+	for _, candidate := range candidates {
+		if remoteTags[candidate] || docker.CheckDockerImageExists(image.GetRepository()+":"+candidate) {
+			log.WithFields(log.Fields{
+				"repository": image.GetRepository(),
+				"tag":        candidate,
+			}).Infof("Using %s, the most recent date tag", candidate)
+
+			return candidate
+		}
+	}
+
+	log.WithFields(log.Fields{
+		"repository": image.GetRepository(),
+		"days":       days,
+		"tag":        date.CurrentDate,
+	}).Warn("There is no date tag in the last days. Using the current date")
+
+	return date.CurrentDate
+}
@@ -20,25 +20,7 @@ var directoryPath string
 func init() {
 	rootCmd.AddCommand(deployCmd)
 
-	subcommands := []*cobra.Command{
-		deployCE, deployCommerce, deployDXP, deployNightly, deployRelease}
-
-	for i := 0; i < len(subcommands); i++ {
-		subcommand := subcommands[i]
-
-		deployCmd.AddCommand(subcommand)
-
-		subcommand.Flags().StringVarP(
-			&filePath, "files", "f", "",
-			`The file or files to deploy. A comma-separated list of files is accepted to deploy multiple files at the same time`)
-
-		subcommand.Flags().StringVarP(
-			&directoryPath, "dir", "d", "",
-			`The directory to deploy its content. Only first-level files will be deployed, so no recursive deployment will happen`)
-
-		subcommand.PersistentFlags().BoolVarP(&verbose, "verbose", "V", false, "Runs commands with Debug log level")
-		subcommand.VisitParents(addVerboseFlag)
-	}
+	addPortalSubcommands(deployCmd, newDeployCmd)
 }
 
 var deployCmd = &cobra.Command{
@@ -51,84 +33,35 @@ var deployCmd = &cobra.Command{
 	Run: func(cmd *cobra.Command, args []string) {
 		SubCommandInfo()
 	},
+	Annotations: map[string]string{containersAnnotation: containersRunning},
 }
 
-var deployCE = &cobra.Command{
-	Use:   "ce",
-	Short: "Deploys files or a directory to Liferay CE's deploy folder in the container run by lpn",
-	Long: `Deploys files or a directory to Liferay CE's deploy folder in the container run by lpn.
+// newDeployCmd returns the subcommand deploying files to the container of a portal image type
+func newDeployCmd(image liferay.Image) *cobra.Command {
+	subcommand := &cobra.Command{
+		Short: "Deploys files or a directory to " + image.GetDescription() + "'s deploy folder in the container run by lpn",
+		Long: `Deploys files or a directory to ` + image.GetDescription() + `'s deploy folder in the container run by lpn.
 	The appropriate tag is calculated from the image the container was build from.`,
-	Run: func(cmd *cobra.Command, args []string) {
-		validateArguments()
+		Run: func(cmd *cobra.Command, args []string) {
+			validateArguments()
 
-		ce := liferay.CE{}
+			imageToDeploy := newImage(image.GetType(), getTag(image))
 
-		ce.Tag = getTag(ce)
+			inspectImageSettings(imageToDeploy)
 
-		doDeploy(ce)
-	},
-}
-
-var deployCommerce = &cobra.Command{
-	Use:   "commerce",
-	Short: "Deploys files or a directory to Liferay Portal's deploy folder in the container run by lpn",
-	Long:  `Deploys files or a directory to Liferay Portal's deploy folder in the container run by lpn`,
-	Run: func(cmd *cobra.Command, args []string) {
-		validateArguments()
-
-		commerce := liferay.Commerce{}
-
-		commerce.Tag = getTag(commerce)
-
-		doDeploy(commerce)
-	},
-}
-
-var deployDXP = &cobra.Command{
-	Use:   "dxp",
-	Short: "Deploys files or a directory to Liferay DXP's deploy folder in the container run by lpn",
-	Long: `Deploys files or a directory to Liferay DXP's deploy folder in the container run by lpn.
-	The appropriate tag is calculated from the image the container was build from.`,
-	Run: func(cmd *cobra.Command, args []string) {
-		validateArguments()
-
-		dxp := liferay.DXP{}
-
-		dxp.Tag = getTag(dxp)
-
-		doDeploy(dxp)
-	},
-}
-
-var deployNightly = &cobra.Command{
-	Use:   "nightly",
-	Short: "Deploys files or a directory to Liferay Portal's deploy folder in the container run by lpn",
-	Long:  `Deploys files or a directory to Liferay Portal's deploy folder in the container run by lpn`,
-	Run: func(cmd *cobra.Command, args []string) {
-		validateArguments()
-
-		nightly := liferay.Nightly{}
-
-		nightly.Tag = getTag(nightly)
-
-		doDeploy(nightly)
-	},
-}
-
-var deployRelease = &cobra.Command{
-	Use:   "release",
-	Short: "Deploys files or a directory to Liferay Portal's deploy folder in the container run by lpn",
-	Long: `Deploys files or a directory to Liferay Portal's deploy folder in the container run by lpn.
-	The appropriate tag is calculated from the image the container was build from.`,
-	Run: func(cmd *cobra.Command, args []string) {
-		validateArguments()
+			doDeploy(imageToDeploy)
+		},
+	}
 
-		release := liferay.Release{}
+	subcommand.Flags().StringVarP(
+		&filePath, "files", "f", "",
+		`The file or files to deploy. A comma-separated list of files is accepted to deploy multiple files at the same time`)
 
-		release.Tag = getTag(release)
+	subcommand.Flags().StringVarP(
+		&directoryPath, "dir", "d", "",
+		`The directory to deploy its content. Only first-level files will be deployed, so no recursive deployment will happen`)
 
-		doDeploy(release)
-	},
+	return subcommand
 }
 
 // deployDirectory deploys a directory's content to the running container
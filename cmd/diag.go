@@ -0,0 +1,128 @@
+package cmd
+
+import (
+	"time"
+
+	docker "github.com/mdelapenya/lpn/docker"
+	liferay "github.com/mdelapenya/lpn/liferay"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var diagDir string
+var threadDumpCount int
+var threadDumpInterval time.Duration
+
+func init() {
+	rootCmd.AddCommand(diagCmd)
+
+	addPortalSubcommands(diagCmd, newDiagCmd)
+}
+
+var diagCmd = &cobra.Command{
+	Use:   "diag",
+	Short: "Captures diagnostics of a Liferay Portal instance",
+	Long: `Captures thread and heap dumps of a running Liferay Portal instance, running the JDK tools inside
+	the container and copying the results to a local timestamped directory.
+	For that, please run this command adding the image type as subcommand (see configuration file).`,
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		enableDebugLevel()
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		SubCommandInfo()
+	},
+	Annotations: map[string]string{containersAnnotation: containersRunning},
+}
+
+// newDiagCmd returns the subcommand capturing diagnostics of the container of a portal image type
+func newDiagCmd(image liferay.Image) *cobra.Command {
+	subcommand := &cobra.Command{
+		Short: "Captures diagnostics of the " + image.GetDescription() + " instance",
+		Long: `Captures diagnostics of the ` + image.GetDescription() + ` instance, identified by [` + image.GetContainerName() + `].
+	For that, please run this command adding threaddump or heapdump as subcommand.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			cmd.Help()
+		},
+	}
+
+	subcommand.PersistentFlags().StringVar(&diagDir, "dir", docker.GetDiagDir(), "Sets the directory where the timestamped directory with the dumps is created")
+
+	threadDumpCmd := &cobra.Command{
+		Use:   "threaddump",
+		Short: "Captures thread dumps of the " + image.GetDescription() + " instance",
+		Long: `Captures thread dumps of the ` + image.GetDescription() + ` instance with jstack, run as [` + image.GetUser() + `].
+	Several dumps can be captured, waiting an interval between them, to compare the state of the threads.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			threadDump(image)
+		},
+	}
+
+	threadDumpCmd.Flags().IntVarP(&threadDumpCount, "count", "c", 1, "Sets the number of thread dumps to capture")
+	threadDumpCmd.Flags().DurationVarP(&threadDumpInterval, "interval", "i", 5*time.Second, "Sets the time to wait between thread dumps")
+
+	heapDumpCmd := &cobra.Command{
+		Use:   "heapdump",
+		Short: "Captures a heap dump of the " + image.GetDescription() + " instance",
+		Long: `Captures a heap dump of the live objects of the ` + image.GetDescription() + ` instance with jmap, run as [` + image.GetUser() + `].
+	The heap dump could be as big as the heap of the portal.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			heapDump(image)
+		},
+	}
+
+	subcommand.AddCommand(threadDumpCmd, heapDumpCmd)
+
+	return subcommand
+}
+
+// checkRunningContainer exits if the container of the image is not running
+func checkRunningContainer(image liferay.Image) {
+	if !docker.IsContainerRunning(image.GetContainerName()) {
+		log.WithFields(log.Fields{
+			"container": image.GetContainerName(),
+		}).Fatal("The container is not running")
+	}
+}
+
+// heapDump captures a heap dump of the running container of the image
+func heapDump(image liferay.Image) {
+	checkRunningContainer(image)
+
+	dir, err := docker.HeapDump(image, diagDir)
+	if err != nil {
+		log.WithFields(log.Fields{
+			"container": image.GetContainerName(),
+			"error":     err,
+		}).Fatal("Impossible to capture the heap dump")
+	}
+
+	log.WithFields(log.Fields{
+		"container": image.GetContainerName(),
+		"dir":       dir,
+	}).Info("The heap dump has been captured")
+}
+
+// threadDump captures thread dumps of the running container of the image
+func threadDump(image liferay.Image) {
+	checkRunningContainer(image)
+
+	if threadDumpCount < 1 {
+		log.WithFields(log.Fields{
+			"count": threadDumpCount,
+		}).Fatal("The number of thread dumps must be greater than zero")
+	}
+
+	dir, err := docker.ThreadDump(image, threadDumpCount, threadDumpInterval, diagDir)
+	if err != nil {
+		log.WithFields(log.Fields{
+			"container": image.GetContainerName(),
+			"error":     err,
+		}).Fatal("Impossible to capture the thread dumps")
+	}
+
+	log.WithFields(log.Fields{
+		"container": image.GetContainerName(),
+		"dir":       dir,
+	}).Info("The thread dumps have been captured")
+}
@@ -0,0 +1,200 @@
+package cmd
+
+import (
+	"os"
+	"sort"
+	"strings"
+
+	docker "github.com/mdelapenya/lpn/docker"
+	liferay "github.com/mdelapenya/lpn/liferay"
+	tablewriter "github.com/olekukonko/tablewriter"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+// maskedValue value shown instead of the sensitive environment variables
+const maskedValue = "********"
+
+var tagToInfo string
+
+func init() {
+	rootCmd.AddCommand(infoCmd)
+
+	addPortalSubcommands(infoCmd, newInfoCmd)
+}
+
+var infoCmd = &cobra.Command{
+	Use:   "info",
+	Short: "Shows what is inside a Liferay Portal image or running instance",
+	Long: `Shows what is inside a Liferay Portal image or running instance: the labels and environment of
+	the image, its creation date and, if the container of the image is running, the release, patch level,
+	JVM and Tomcat versions queried to the portal.
+	For that, please run this command adding the image type as subcommand (see configuration file).`,
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		enableDebugLevel()
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		SubCommandInfo()
+	},
+	Annotations: map[string]string{containersAnnotation: containersAll},
+}
+
+// newInfoCmd returns the subcommand showing the information of a portal image type
+func newInfoCmd(image liferay.Image) *cobra.Command {
+	subcommand := &cobra.Command{
+		Short: "Shows what is inside a " + image.GetDescription() + " image or running instance",
+		Long: `Shows what is inside a ` + image.GetDescription() + ` image or running instance, identified by [` + image.GetContainerName() + `].
+	If no image tag is passed to the command, the image of the container will be used, or the default
+	tag if there is no container.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			showInfo(image.GetType())
+		},
+	}
+
+	subcommand.Flags().StringVarP(&tagToInfo, "tag", "t", "", "Sets the image tag to show")
+
+	return subcommand
+}
+
+// maskEnv returns a copy of the environment, hiding the values of the variables which could hold
+// credentials, so that the report can be shared
+func maskEnv(env map[string]string) map[string]string {
+	masked := map[string]string{}
+
+	for key, value := range env {
+		upperKey := strings.ToUpper(key)
+
+		if strings.Contains(upperKey, "PASSWORD") || strings.Contains(upperKey, "SECRET") ||
+			strings.Contains(upperKey, "TOKEN") {
+
+			value = maskedValue
+		}
+
+		masked[key] = value
+	}
+
+	return masked
+}
+
+// printInfoAsTable prints the report as a two-column table, ready to be pasted into tickets
+func printInfoAsTable(info InfoOutput) {
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"Property", "Value"})
+	table.SetAutoWrapText(false)
+
+	table.Append([]string{"Image", info.Image})
+	table.Append([]string{"ID", info.ID})
+	table.Append([]string{"Created", info.Created})
+	table.Append([]string{"OS/Arch", info.OS + "/" + info.Architecture})
+
+	if info.Container != "" {
+		table.Append([]string{"Container", info.Container})
+	}
+
+	if info.Release != nil {
+		table.Append([]string{"Release", info.Release.Name})
+
+		if info.Release.Build != "" {
+			table.Append([]string{"Build", info.Release.Build + " (" + info.Release.Codename + ", " + info.Release.Date + ")"})
+		}
+	}
+
+	if info.PatchLevel != "" {
+		table.Append([]string{"Patch level", info.PatchLevel})
+	}
+
+	if info.JVM != "" {
+		table.Append([]string{"JVM", info.JVM})
+	}
+
+	if info.Tomcat != "" {
+		table.Append([]string{"Tomcat", info.Tomcat})
+	}
+
+	for _, key := range sortedKeys(info.Labels) {
+		table.Append([]string{"Label " + key, info.Labels[key]})
+	}
+
+	for _, key := range sortedKeys(info.Env) {
+		table.Append([]string{"Env " + key, info.Env[key]})
+	}
+
+	table.Render()
+}
+
+// showInfo builds the report of the image of a portal image type, querying its container if it
+// is running the same image
+func showInfo(t string) {
+	image := newImage(t, "")
+
+	containerTag := ""
+	if docker.CheckDockerContainerExists(image.GetContainerName()) {
+		containerTag = getTag(image)
+	}
+
+	tag := tagToInfo
+	if tag == "" {
+		tag = containerTag
+	}
+
+	image = newImage(t, getImageTag(image, tag, false))
+
+	imageInfo, err := docker.GetImageInfo(image)
+	if err != nil {
+		log.WithFields(log.Fields{
+			"image": image.GetFullyQualifiedName(),
+			"error": err,
+		}).Fatal("Impossible to inspect the image. Please pull it first")
+	}
+
+	info := InfoOutput{
+		Architecture: imageInfo.Architecture,
+		Created:      imageInfo.Created,
+		Env:          maskEnv(imageInfo.Env),
+		ID:           imageInfo.ID,
+		Image:        image.GetFullyQualifiedName(),
+		JVM:          imageInfo.Env["JAVA_VERSION"],
+		Labels:       imageInfo.Labels,
+		OS:           imageInfo.OS,
+	}
+
+	if productName := imageInfo.Env["LIFERAY_PRODUCT_NAME"]; productName != "" {
+		info.Release = &liferay.ReleaseInfo{Name: productName}
+	}
+
+	if containerTag == image.GetTag() && docker.IsContainerRunning(image.GetContainerName()) {
+		portalInfo := docker.GetPortalInfo(image)
+
+		info.Container = image.GetContainerName()
+		info.PatchLevel = portalInfo.PatchLevel
+
+		if portalInfo.Release.Name != "" {
+			info.Release = &portalInfo.Release
+		}
+
+		if jvm := portalInfo.ServerInfo["JVM Version"]; jvm != "" {
+			info.JVM = jvm
+		}
+
+		info.Tomcat = portalInfo.ServerInfo["Server version"]
+	}
+
+	if isStructuredOutput() {
+		printStructuredOutput(info)
+		return
+	}
+
+	printInfoAsTable(info)
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := []string{}
+	for key := range m {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
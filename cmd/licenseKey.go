@@ -0,0 +1,142 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	docker "github.com/mdelapenya/lpn/docker"
+	liferay "github.com/mdelapenya/lpn/liferay"
+	tablewriter "github.com/olekukonko/tablewriter"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+// licenseExpirationDays days before the expiration of an activation key from which lpn warns
+const licenseExpirationDays = 30
+
+var licenseDays int
+
+func init() {
+	rootCmd.AddCommand(licenseKeyCmd)
+
+	addPortalSubcommands(licenseKeyCmd, newLicenseKeyCmd)
+}
+
+var licenseKeyCmd = &cobra.Command{
+	Use:   "license-key",
+	Short: "Shows the activation key of a Liferay DXP instance",
+	Long: `Shows the owner, type and expiration date of the activation key a Liferay DXP instance was run
+	with, warning if it expires soon.
+	For that, please run this command adding the image type as subcommand (see configuration file).`,
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		enableDebugLevel()
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		SubCommandInfo()
+	},
+	Annotations: map[string]string{containersAnnotation: containersRunning},
+}
+
+// newLicenseKeyCmd returns the subcommand showing the activation key of a portal image type, if
+// the image type requires one
+func newLicenseKeyCmd(image liferay.Image) *cobra.Command {
+	if !image.RequiresLicense() {
+		return nil
+	}
+
+	subcommand := &cobra.Command{
+		Short: "Shows the activation key of a " + image.GetDescription() + " instance",
+		Long: `Shows the owner, type and expiration date of the activation key the ` + image.GetDescription() + ` instance,
+	identified by [` + image.GetContainerName() + `], was run with.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			showLicenseKey(image)
+		},
+	}
+
+	subcommand.Flags().IntVar(&licenseDays, "days", licenseExpirationDays, "Warns if the activation key expires within this number of days")
+
+	return subcommand
+}
+
+// checkLicense validates the activation key file, warning if it expires within a number of days,
+// and returns its absolute path
+func checkLicense(path string, days int) string {
+	absolutePath, err := filepath.Abs(path)
+	if err != nil {
+		log.WithFields(log.Fields{
+			"license": path,
+			"error":   err,
+		}).Fatal("Impossible to resolve the path of the activation key")
+	}
+
+	license, err := liferay.ReadLicense(absolutePath)
+	if err != nil {
+		log.WithFields(log.Fields{
+			"license": absolutePath,
+			"error":   err,
+		}).Fatal("Impossible to read the activation key")
+	}
+
+	warnLicenseExpiration(license, days)
+
+	return absolutePath
+}
+
+// showLicenseKey shows the activation key the container of the image was run with
+func showLicenseKey(image liferay.Image) {
+	license, path, err := docker.GetLicense(image)
+	if err != nil {
+		log.WithFields(log.Fields{
+			"container": image.GetContainerName(),
+			"error":     err,
+		}).Fatal("Impossible to read the activation key of the container")
+	}
+
+	warnLicenseExpiration(license, licenseDays)
+
+	if isStructuredOutput() {
+		printStructuredOutput(LicenseKeyOutput{
+			Container:      image.GetContainerName(),
+			ExpirationDate: license.ExpirationDate,
+			File:           path,
+			LicenseType:    license.LicenseType,
+			Owner:          license.Owner,
+		})
+		return
+	}
+
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"Container", "Owner", "Type", "Expiration Date", "File"})
+
+	table.Append([]string{
+		image.GetContainerName(), license.Owner, license.LicenseType, license.ExpirationDate, path})
+
+	table.Render()
+}
+
+// warnLicenseExpiration warns if the activation key has expired or expires within a number of days
+func warnLicenseExpiration(license liferay.License, days int) {
+	daysToExpire, err := license.GetDaysToExpire(time.Now())
+	if err != nil {
+		log.WithFields(log.Fields{
+			"expirationDate": license.ExpirationDate,
+			"error":          err,
+		}).Warn("Impossible to check the expiration of the activation key")
+		return
+	}
+
+	if daysToExpire < 0 {
+		log.WithFields(log.Fields{
+			"expirationDate": license.ExpirationDate,
+			"owner":          license.Owner,
+		}).Warn("The activation key has expired")
+	} else if daysToExpire <= days {
+		log.WithFields(log.Fields{
+			"expirationDate": license.ExpirationDate,
+			"owner":          license.Owner,
+		}).Warn("The activation key expires in " + strconv.Itoa(daysToExpire) + " days")
+	}
+}
@@ -1,26 +1,33 @@
 package cmd
 
 import (
+	"io"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+
 	docker "github.com/mdelapenya/lpn/docker"
 	liferay "github.com/mdelapenya/lpn/liferay"
+	tablewriter "github.com/olekukonko/tablewriter"
 
+	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 )
 
+var logDatabase bool
+var logGrep string
+var logLevel string
+var logNoFollow bool
+var logSince string
+var logSummary bool
+var logTail int
+var logTimestamps bool
+
 func init() {
 	rootCmd.AddCommand(logCmd)
 
-	subcommands := []*cobra.Command{
-		logCECmd, logCommerceCmd, logDXPCmd, logNightlyCmd, logReleaseCmd}
-
-	for i := 0; i < len(subcommands); i++ {
-		subcommand := subcommands[i]
-
-		logCmd.AddCommand(subcommand)
-
-		subcommand.PersistentFlags().BoolVarP(&verbose, "verbose", "V", false, "Runs commands with Debug log level")
-		subcommand.VisitParents(addVerboseFlag)
-	}
+	addPortalSubcommands(logCmd, newLogCmd)
 }
 
 var logCmd = &cobra.Command{
@@ -33,64 +40,165 @@ var logCmd = &cobra.Command{
 	Run: func(cmd *cobra.Command, args []string) {
 		SubCommandInfo()
 	},
+	Annotations: map[string]string{containersAnnotation: containersRunning},
 }
 
-var logCECmd = &cobra.Command{
-	Use:   "ce",
-	Short: "Displays logs for the Liferay Portal CE instance",
-	Long:  `Displays logs for the Liferay Portal CE instance, identified by [lpn-ce].`,
-	Run: func(cmd *cobra.Command, args []string) {
-		ce := liferay.CE{}
+// newLogCmd returns the subcommand displaying the logs of the container of a portal image type
+func newLogCmd(image liferay.Image) *cobra.Command {
+	subcommand := &cobra.Command{
+		Short: "Displays logs for the " + image.GetDescription() + " instance",
+		Long: `Displays logs for the ` + image.GetDescription() + ` instance, identified by [` + image.GetContainerName() + `].
+	The logs are followed from the beginning, unless the tail, since or no-follow flags are passed.
+	The log lines can be filtered by level and by a regular expression, keeping the stack traces with them.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			logContainer(image)
+		},
+	}
 
-		logContainer(ce)
-	},
+	subcommand.Flags().BoolVar(&logDatabase, "db", false, "Displays the logs of the database container of the stack instead")
+	subcommand.Flags().StringVar(&logGrep, "grep", "", "Displays the log lines matching a regular expression, with their stack traces")
+	subcommand.Flags().StringVar(&logLevel, "level", "", "Displays the log lines of this level and above. Supported values are [TRACE|DEBUG|INFO|WARN|ERROR|FATAL]")
+	subcommand.Flags().BoolVar(&logNoFollow, "no-follow", false, "Displays the current logs and exits, instead of following them")
+	subcommand.Flags().StringVar(&logSince, "since", "", "Displays the logs since a timestamp, as '2019-11-04T10:21:00', or a relative time, as '10m'")
+	subcommand.Flags().BoolVar(&logSummary, "summary", false, "Counts the errors by logger after displaying the current logs. It implies no-follow")
+	subcommand.Flags().IntVar(&logTail, "tail", -1, "Displays this number of lines from the end of the logs (default all)")
+	subcommand.Flags().BoolVar(&logTimestamps, "timestamps", false, "Displays the timestamp Docker received each line at")
+
+	return subcommand
 }
 
-var logCommerceCmd = &cobra.Command{
-	Use:   "commerce",
-	Short: "Displays logs for the Liferay Portal Commerce instance",
-	Long:  `Displays logs for the Liferay Portal Commerce instance, identified by [lpn-commerce].`,
-	Run: func(cmd *cobra.Command, args []string) {
-		commerce := liferay.Commerce{}
+// isTerminal checks if a file is a terminal, to decide if the output can be colorized
+func isTerminal(file *os.File) bool {
+	if os.Getenv("NO_COLOR") != "" {
+		return false
+	}
 
-		logContainer(commerce)
-	},
+	fileInfo, err := file.Stat()
+	if err != nil {
+		return false
+	}
+
+	return fileInfo.Mode()&os.ModeCharDevice != 0
 }
 
-var logDXPCmd = &cobra.Command{
-	Use:   "dxp",
-	Short: "Displays logs for the Liferay DXP instance",
-	Long:  `Displays logs for the Liferay DXP instance, identified by [lpn-dxp].`,
-	Run: func(cmd *cobra.Command, args []string) {
-		dxp := liferay.DXP{}
+// logContainer show the logs for the running container of the specified type
+func logContainer(image liferay.Image) {
+	containerName := image.GetContainerName()
+
+	if logDatabase {
+		dbContainerName, err := docker.GetDatabaseContainerName(image)
+		if err != nil {
+			log.WithFields(log.Fields{
+				"container": containerName,
+				"error":     err,
+			}).Fatal("Could not find the database container")
+		}
+
+		containerName = dbContainerName
+	}
 
-		logContainer(dxp)
-	},
-}
+	tail := "all"
+	if logTail >= 0 {
+		tail = strconv.Itoa(logTail)
+	}
 
-var logNightlyCmd = &cobra.Command{
-	Use:   "nightly",
-	Short: "Displays logs for the Liferay Portal Nightly Build instance",
-	Long:  `Displays logs for the Liferay Portal Nightly Build instance, identified by [lpn-nightly].`,
-	Run: func(cmd *cobra.Command, args []string) {
-		nightly := liferay.Nightly{}
+	var stdout io.Writer = os.Stdout
+	var stderr io.Writer = os.Stderr
 
-		logContainer(nightly)
-	},
+	filters := []*liferay.LogFilter{}
+
+	if logLevel != "" || logGrep != "" || logSummary || isTerminal(os.Stdout) {
+		stdoutFilter := newLogFilter(os.Stdout, isTerminal(os.Stdout))
+		stderrFilter := newLogFilter(os.Stderr, isTerminal(os.Stderr))
+
+		filters = append(filters, stdoutFilter, stderrFilter)
+		stdout = stdoutFilter
+		stderr = stderrFilter
+	}
+
+	err := docker.LogContainer(containerName, docker.LogOptions{
+		Follow:     !logNoFollow && !logSummary,
+		Since:      logSince,
+		Tail:       tail,
+		Timestamps: logTimestamps,
+	}, stdout, stderr)
+
+	for _, filter := range filters {
+		filter.Flush()
+	}
+
+	if err != nil {
+		log.WithFields(log.Fields{
+			"container": containerName,
+			"error":     err,
+		}).Fatal("Could not get container logs")
+	}
+
+	if logSummary {
+		printErrorsSummary(filters)
+	}
 }
 
-var logReleaseCmd = &cobra.Command{
-	Use:   "release",
-	Short: "Displays logs for the Liferay Portal Release instance",
-	Long:  `Displays logs for the Liferay Portal Release instance, identified by [lpn-release].`,
-	Run: func(cmd *cobra.Command, args []string) {
-		release := liferay.Release{}
+// newLogFilter returns a filter of the logs with the level and regular expression of the flags
+func newLogFilter(out io.Writer, colorize bool) *liferay.LogFilter {
+	filter := liferay.NewLogFilter(out)
+	filter.Colorize = colorize
+
+	if logLevel != "" {
+		level, err := liferay.ValidateLogLevel(logLevel)
+		if err != nil {
+			log.WithFields(log.Fields{
+				"level": logLevel,
+				"error": err,
+			}).Fatal("Invalid level")
+		}
+
+		filter.Level = level
+	}
 
-		logContainer(release)
-	},
+	if logGrep != "" {
+		grep, err := regexp.Compile(logGrep)
+		if err != nil {
+			log.WithFields(log.Fields{
+				"grep":  logGrep,
+				"error": err,
+			}).Fatal("Invalid regular expression")
+		}
+
+		filter.Grep = grep
+	}
+
+	return filter
 }
 
-// logContainer show the logs for the running container of the specified type
-func logContainer(image liferay.Image) {
-	docker.LogContainer(image)
+// printErrorsSummary prints the number of errors per logger, from the most to the least frequent
+func printErrorsSummary(filters []*liferay.LogFilter) {
+	errors := map[string]int{}
+	for _, filter := range filters {
+		for logger, count := range filter.GetErrorsByLogger() {
+			errors[logger] += count
+		}
+	}
+
+	loggers := []string{}
+	for logger := range errors {
+		loggers = append(loggers, logger)
+	}
+
+	sort.Slice(loggers, func(i, j int) bool {
+		if errors[loggers[i]] != errors[loggers[j]] {
+			return errors[loggers[i]] > errors[loggers[j]]
+		}
+
+		return loggers[i] < loggers[j]
+	})
+
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"Logger", "Errors"})
+
+	for _, logger := range loggers {
+		table.Append([]string{logger, strconv.Itoa(errors[logger])})
+	}
+
+	table.Render()
 }
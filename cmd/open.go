@@ -15,17 +15,7 @@ import (
 func init() {
 	rootCmd.AddCommand(openCmd)
 
-	subcommands := []*cobra.Command{
-		openCECmd, openCommerceCmd, openDXPCmd, openNightlyCmd, openReleaseCmd}
-
-	for i := 0; i < len(subcommands); i++ {
-		subcommand := subcommands[i]
-
-		subcommand.PersistentFlags().BoolVarP(&verbose, "verbose", "V", false, "Runs commands with Debug log level")
-		subcommand.VisitParents(addVerboseFlag)
-
-		openCmd.AddCommand(subcommand)
-	}
+	addPortalSubcommands(openCmd, newOpenCmd)
 }
 
 var openCmd = &cobra.Command{
@@ -38,61 +28,18 @@ var openCmd = &cobra.Command{
 	Run: func(cmd *cobra.Command, args []string) {
 		SubCommandInfo()
 	},
+	Annotations: map[string]string{containersAnnotation: containersRunning},
 }
 
-var openCECmd = &cobra.Command{
-	Use:   "ce",
-	Short: "Opens a browser with  the Liferay Portal CE instance",
-	Long:  `Opens a browser with  the Liferay Portal CE instance, identified by [lpn-ce].`,
-	Run: func(cmd *cobra.Command, args []string) {
-		ce := liferay.CE{}
-
-		openBrowser(ce)
-	},
-}
-
-var openCommerceCmd = &cobra.Command{
-	Use:   "commerce",
-	Short: "Opens a browser with  the Liferay Portal Commerce instance",
-	Long:  `Opens a browser with  the Liferay Portal Commerce instance, identified by [lpn-commerce].`,
-	Run: func(cmd *cobra.Command, args []string) {
-		commerce := liferay.Commerce{}
-
-		openBrowser(commerce)
-	},
-}
-
-var openDXPCmd = &cobra.Command{
-	Use:   "dxp",
-	Short: "Opens a browser with  the Liferay DXP instance",
-	Long:  `Opens a browser with  the Liferay DXP instance, identified by [lpn-dxp].`,
-	Run: func(cmd *cobra.Command, args []string) {
-		dxp := liferay.DXP{}
-
-		openBrowser(dxp)
-	},
-}
-
-var openNightlyCmd = &cobra.Command{
-	Use:   "nightly",
-	Short: "Opens a browser with  the Liferay Portal Nightly Build instance",
-	Long:  `Opens a browser with  the Liferay Portal Nightly Build instance, identified by [lpn-nightly].`,
-	Run: func(cmd *cobra.Command, args []string) {
-		nightly := liferay.Nightly{}
-
-		openBrowser(nightly)
-	},
-}
-
-var openReleaseCmd = &cobra.Command{
-	Use:   "release",
-	Short: "Opens a browser with the Liferay Portal Release instance",
-	Long:  `Opens a browser with  the Liferay Portal Release instance, identified by [lpn-release].`,
-	Run: func(cmd *cobra.Command, args []string) {
-		release := liferay.Release{}
-
-		openBrowser(release)
-	},
+// newOpenCmd returns the subcommand opening a browser with the container of a portal image type
+func newOpenCmd(image liferay.Image) *cobra.Command {
+	return &cobra.Command{
+		Short: "Opens a browser with the " + image.GetDescription() + " instance",
+		Long:  `Opens a browser with the ` + image.GetDescription() + ` instance, identified by [` + image.GetContainerName() + `].`,
+		Run: func(cmd *cobra.Command, args []string) {
+			openBrowser(image)
+		},
+	}
 }
 
 // openBrowser opens a browser the running container
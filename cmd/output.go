@@ -0,0 +1,131 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	docker "github.com/mdelapenya/lpn/docker"
+	liferay "github.com/mdelapenya/lpn/liferay"
+
+	log "github.com/sirupsen/logrus"
+	yaml "gopkg.in/yaml.v2"
+)
+
+const outputJSON = "json"
+const outputTable = "table"
+const outputYAML = "yaml"
+
+var outputFormat string
+
+// CheckContainerOutput structured result of the checkc command
+type CheckContainerOutput struct {
+	Container string                    `json:"container" yaml:"container"`
+	Exists    bool                      `json:"exists" yaml:"exists"`
+	Instance  *docker.ContainerInstance `json:"instance,omitempty" yaml:"instance,omitempty"`
+}
+
+// CheckImageOutput structured result of the checki command
+type CheckImageOutput struct {
+	Image  string `json:"image" yaml:"image"`
+	Exists bool   `json:"exists" yaml:"exists"`
+}
+
+// InfoOutput structured result of the info command
+type InfoOutput struct {
+	Image        string               `json:"image" yaml:"image"`
+	ID           string               `json:"id" yaml:"id"`
+	Created      string               `json:"created" yaml:"created"`
+	OS           string               `json:"os" yaml:"os"`
+	Architecture string               `json:"architecture" yaml:"architecture"`
+	Container    string               `json:"container,omitempty" yaml:"container,omitempty"`
+	Release      *liferay.ReleaseInfo `json:"release,omitempty" yaml:"release,omitempty"`
+	PatchLevel   string               `json:"patchLevel,omitempty" yaml:"patchLevel,omitempty"`
+	JVM          string               `json:"jvm,omitempty" yaml:"jvm,omitempty"`
+	Tomcat       string               `json:"tomcat,omitempty" yaml:"tomcat,omitempty"`
+	Labels       map[string]string    `json:"labels" yaml:"labels"`
+	Env          map[string]string    `json:"env" yaml:"env"`
+}
+
+// LicenseKeyOutput structured result of the license-key command
+type LicenseKeyOutput struct {
+	Container      string `json:"container" yaml:"container"`
+	Owner          string `json:"owner" yaml:"owner"`
+	LicenseType    string `json:"licenseType" yaml:"licenseType"`
+	ExpirationDate string `json:"expirationDate" yaml:"expirationDate"`
+	File           string `json:"file" yaml:"file"`
+}
+
+// ProfileOutput structured representation of a profile in the profile list command
+type ProfileOutput struct {
+	Name      string `json:"name" yaml:"name"`
+	Current   bool   `json:"current" yaml:"current"`
+	Workspace string `json:"workspace" yaml:"workspace"`
+}
+
+// TagOutput structured representation of a tag in the tags command
+type TagOutput struct {
+	Name          string   `json:"name" yaml:"name"`
+	Size          int      `json:"size" yaml:"size"`
+	LastUpdated   string   `json:"lastUpdated,omitempty" yaml:"lastUpdated,omitempty"`
+	Architectures []string `json:"architectures" yaml:"architectures"`
+}
+
+// TagsOutput structured result of the tags command
+type TagsOutput struct {
+	Repository  string      `json:"repository" yaml:"repository"`
+	Count       int         `json:"count" yaml:"count"`
+	CurrentPage int         `json:"currentPage" yaml:"currentPage"`
+	TotalPages  int         `json:"totalPages" yaml:"totalPages"`
+	Tags        []TagOutput `json:"tags" yaml:"tags"`
+}
+
+// VersionOutput structured result of the version command
+type VersionOutput struct {
+	Lpn          string `json:"lpn" yaml:"lpn"`
+	DockerClient string `json:"dockerClient" yaml:"dockerClient"`
+	DockerServer string `json:"dockerServer" yaml:"dockerServer"`
+	Golang       string `json:"golang" yaml:"golang"`
+}
+
+// configureOutput validates the output format, sending the logs to stderr when the output is
+// a structured one, so that stdout only contains the structured document
+func configureOutput() {
+	switch outputFormat {
+	case outputTable:
+		return
+	case outputJSON, outputYAML:
+		log.SetOutput(os.Stderr)
+	default:
+		log.WithFields(log.Fields{
+			"output": outputFormat,
+		}).Fatal("Non supported output format. Supported values are [table|json|yaml]")
+	}
+}
+
+// isStructuredOutput returns if the output format is a structured one (JSON or YAML)
+func isStructuredOutput() bool {
+	return outputFormat == outputJSON || outputFormat == outputYAML
+}
+
+// printStructuredOutput writes the result to stdout, using the format selected by the output flag
+func printStructuredOutput(result interface{}) {
+	var bytes []byte
+	var err error
+
+	if outputFormat == outputYAML {
+		bytes, err = yaml.Marshal(result)
+	} else {
+		bytes, err = json.MarshalIndent(result, "", "  ")
+		bytes = append(bytes, '\n')
+	}
+
+	if err != nil {
+		log.WithFields(log.Fields{
+			"output": outputFormat,
+			"error":  err,
+		}).Fatal("Could not serialise the output")
+	}
+
+	fmt.Print(string(bytes))
+}
@@ -0,0 +1,85 @@
+package cmd
+
+import (
+	docker "github.com/mdelapenya/lpn/docker"
+	internal "github.com/mdelapenya/lpn/internal"
+	liferay "github.com/mdelapenya/lpn/liferay"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+// portalCommand command with one subcommand per portal image type, built by newSubcommand
+type portalCommand struct {
+	parent        *cobra.Command
+	newSubcommand func(image liferay.Image) *cobra.Command
+}
+
+var portalCommands = []portalCommand{}
+
+// addPortalSubcommands registers a command to add it the subcommands of the portal image types
+// defined in the configuration file, which is read once the commands have been initialised.
+// The image types the command does not apply to get a nil subcommand
+func addPortalSubcommands(parent *cobra.Command, newSubcommand func(image liferay.Image) *cobra.Command) {
+	portalCommands = append(portalCommands, portalCommand{parent: parent, newSubcommand: newSubcommand})
+}
+
+// generatePortalSubcommands adds a subcommand per portal image type to the registered commands
+func generatePortalSubcommands() {
+	for _, t := range internal.LpnConfig.GetPortalTypes() {
+		for _, portalCommand := range portalCommands {
+			subcommand := portalCommand.newSubcommand(newImage(t, ""))
+			if subcommand == nil {
+				continue
+			}
+
+			subcommand.Use = t
+
+			portalCommand.parent.AddCommand(subcommand)
+
+			subcommand.PersistentFlags().BoolVarP(&verbose, "verbose", "V", false, "Runs commands with Debug log level")
+			subcommand.VisitParents(addVerboseFlag)
+		}
+	}
+}
+
+// getImageTag returns the tag passed to a command, or the default tag of the image type if empty.
+// For images tagged by date, the remote argument tells if the date tags must be looked up in the
+// registry or in the local Docker installation
+func getImageTag(image liferay.Image, tag string, remote bool) string {
+	if image.HasDateTags() {
+		return getDateTag(image, tag, remote)
+	}
+
+	if tag == "" {
+		return internal.LpnConfig.GetPortalImageTag(image.GetType())
+	}
+
+	return tag
+}
+
+// inspectImageSettings detects the settings of a local portal image, warning if it's not possible
+func inspectImageSettings(image liferay.Image) {
+	err := docker.InspectImageSettings(image)
+	if err != nil {
+		log.WithFields(log.Fields{
+			"image": image.GetFullyQualifiedName(),
+			"error": err,
+		}).Warn("Could not detect the settings of the image. Using the configured ones")
+	}
+}
+
+// newImage returns the image of a portal image type with a tag
+func newImage(t string, tag string) liferay.Image {
+	return liferay.Portal{Tag: tag, Type: t}
+}
+
+// getDefaultTagHelp returns the help text describing the default tag of a portal image type
+func getDefaultTagHelp(image liferay.Image) string {
+	if image.HasDateTags() {
+		return `If no image tag is passed to the command, the tag representing the most recent date with an
+	image will be used, looking back up to the configured number of days.`
+	}
+
+	return `If no image tag is passed to the command, the default tag (see configuration file) will be used.`
+}
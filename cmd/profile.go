@@ -0,0 +1,77 @@
+package cmd
+
+import (
+	"os"
+
+	internal "github.com/mdelapenya/lpn/internal"
+	tablewriter "github.com/olekukonko/tablewriter"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	rootCmd.AddCommand(profileCmd)
+
+	profileCmd.AddCommand(profileListCmd)
+}
+
+var profileCmd = &cobra.Command{
+	Use:   "profile",
+	Short: "Manages the profiles of lpn",
+	Long: `Manages the profiles of lpn. Each profile has its own configuration file, caches and data
+	directories, and its containers get the name of the profile, so that the stacks of different profiles
+	do not collide. The profile is selected with the profile flag or the LPN_PROFILE environment variable,
+	and it's created the first time a stack is run with it.`,
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		enableDebugLevel()
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		cmd.Help()
+	},
+}
+
+var profileListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "Lists the profiles",
+	Long:  `Lists the profiles and their workspaces, marking the one in use.`,
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		profiles, err := internal.GetProfiles()
+		if err != nil {
+			log.WithFields(log.Fields{
+				"error": err,
+			}).Fatal("Could not read the profiles")
+		}
+
+		result := []ProfileOutput{}
+		for _, p := range profiles {
+			result = append(result, ProfileOutput{
+				Current:   p == internal.LpnProfile,
+				Name:      p,
+				Workspace: internal.GetProfileWorkspace(p),
+			})
+		}
+
+		if isStructuredOutput() {
+			printStructuredOutput(result)
+			return
+		}
+
+		table := tablewriter.NewWriter(os.Stdout)
+		table.SetHeader([]string{"Profile", "Current", "Workspace"})
+		table.SetAlignment(tablewriter.ALIGN_LEFT)
+		table.SetAutoWrapText(false)
+
+		for _, p := range result {
+			current := ""
+			if p.Current {
+				current = "*"
+			}
+
+			table.Append([]string{p.Name, current, p.Workspace})
+		}
+
+		table.Render()
+	},
+}
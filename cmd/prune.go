@@ -24,12 +24,9 @@ var pruneCmd = &cobra.Command{
 		enableDebugLevel()
 	},
 	Run: func(cmd *cobra.Command, args []string) {
-		images := []liferay.Image{
-			liferay.CE{Tag: internal.LpnConfig.GetPortalImageTag("ce")},
-			liferay.Commerce{Tag: internal.LpnConfig.GetPortalImageTag("commerce")},
-			liferay.DXP{Tag: internal.LpnConfig.GetPortalImageTag("dxp")},
-			liferay.Nightly{Tag: internal.LpnConfig.GetPortalImageTag("nightly")},
-			liferay.Release{Tag: internal.LpnConfig.GetPortalImageTag("release")},
+		images := []liferay.Image{}
+		for _, t := range internal.LpnConfig.GetPortalTypes() {
+			images = append(images, newImage(t, internal.LpnConfig.GetPortalImageTag(t)))
 		}
 
 		removeLPNContainers(images)
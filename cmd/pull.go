@@ -3,9 +3,7 @@ package cmd
 import (
 	"errors"
 
-	date "github.com/mdelapenya/lpn/date"
 	docker "github.com/mdelapenya/lpn/docker"
-	internal "github.com/mdelapenya/lpn/internal"
 	liferay "github.com/mdelapenya/lpn/liferay"
 
 	log "github.com/sirupsen/logrus"
@@ -18,19 +16,7 @@ var forceRemoval bool
 func init() {
 	rootCmd.AddCommand(pullCmd)
 
-	subcommands := []*cobra.Command{pullCE, pullCommerce, pullDXP, pullNightly, pullRelease}
-
-	for i := 0; i < len(subcommands); i++ {
-		subcommand := subcommands[i]
-
-		pullCmd.AddCommand(subcommand)
-
-		subcommand.Flags().BoolVarP(&forceRemoval, "forceRemoval", "f", false, "Removes the cached, local image, if exists")
-		subcommand.Flags().StringVarP(&tagToPull, "tag", "t", "", "Sets the image tag to pull")
-
-		subcommand.PersistentFlags().BoolVarP(&verbose, "verbose", "V", false, "Runs commands with Debug log level")
-		subcommand.VisitParents(addVerboseFlag)
-	}
+	addPortalSubcommands(pullCmd, newPullCmd)
 }
 
 var pullCmd = &cobra.Command{
@@ -38,7 +24,7 @@ var pullCmd = &cobra.Command{
 	Short: "Pulls a Liferay Portal Docker image",
 	Long: `Pulls a Liferay Portal Docker image from one of the Official repositories (see configuration file).
 		For non-official Docker images, the tool pulls from the official repositories (see configuration file)
-	For that, please run this command adding "ce", "commerce", "dxp", "release" or "nightly" subcommands.`,
+	For that, please run this command adding the image type as subcommand (see configuration file).`,
 	Args: func(cmd *cobra.Command, args []string) error {
 		if len(args) > 1 {
 			return errors.New("pull requires zero or one argument representing the image tag to be pulled")
@@ -54,119 +40,36 @@ var pullCmd = &cobra.Command{
 	},
 }
 
-var pullCE = &cobra.Command{
-	Use:   "ce",
-	Short: "Pulls a Liferay Portal CE Docker image from Official CE repository",
-	Long: `Pulls a Liferay Portal instance, obtained from the official CE repository.
-	If no image tag is passed to the command, the default tag (see configuration file) will be used.`,
-	Args: func(cmd *cobra.Command, args []string) error {
-		if len(args) > 1 {
-			return errors.New("pull ce requires zero or one argument representing the image tag to be pulled")
-		}
-
-		return nil
-	},
-	Run: func(cmd *cobra.Command, args []string) {
-		if tagToPull == "" {
-			tagToPull = internal.LpnConfig.GetPortalImageTag("ce")
-		}
-
-		ce := liferay.CE{Tag: tagToPull}
-
-		pullDockerImage(ce, forceRemoval)
-	},
-}
-
-var pullCommerce = &cobra.Command{
-	Use:   "commerce",
-	Short: "Pulls a Liferay Portal Docker image from Commerce Builds",
-	Long: `Pulls a Liferay Portal Docker image from the Commerce Builds repository".
-	If no image tag is passed to the command, the default tag (see configuration file) will be used.`,
-	Args: func(cmd *cobra.Command, args []string) error {
-		if len(args) > 1 {
-			return errors.New("pull commerce requires zero or one argument representing the image tag to be pulled")
-		}
-
-		return nil
-	},
-	Run: func(cmd *cobra.Command, args []string) {
-		if tagToPull == "" {
-			tagToPull = internal.LpnConfig.GetPortalImageTag("commerce")
-		}
-
-		commerce := liferay.Commerce{Tag: tagToPull}
-
-		pullDockerImage(commerce, forceRemoval)
-	},
-}
-
-var pullDXP = &cobra.Command{
-	Use:   "dxp",
-	Short: "Pulls a Liferay DXP Docker image from Official DXP repository",
-	Long: `Pulls a Liferay DXP instance, obtained from the official DXP repository, including a 30-day activation key.
-	If no image tag is passed to the command, the default tag (see configuration file) will be used.`,
-	Args: func(cmd *cobra.Command, args []string) error {
-		if len(args) > 1 {
-			return errors.New("pull ce requires zero or one argument representing the image tag to be pulled")
-		}
-
-		return nil
-	},
-	Run: func(cmd *cobra.Command, args []string) {
-		if tagToPull == "" {
-			tagToPull = internal.LpnConfig.GetPortalImageTag("dxp")
-		}
-
-		dxp := liferay.DXP{Tag: tagToPull}
-
-		pullDockerImage(dxp, forceRemoval)
-	},
-}
-
-var pullNightly = &cobra.Command{
-	Use:   "nightly",
-	Short: "Pulls a Liferay Portal Docker image from Nightly Builds",
-	Long: `Pulls a Liferay Portal Docker image from the Nightly Builds repository.
-	If no image tag is passed to the command, the "master" tag will be used.`,
-	Args: func(cmd *cobra.Command, args []string) error {
-		if len(args) > 1 {
-			return errors.New("pull nightly requires zero or one argument representing the image tag to be pulled")
-		}
-
-		return nil
-	},
-	Run: func(cmd *cobra.Command, args []string) {
-		if tagToPull == "" {
-			tagToPull = date.CurrentDate
-		}
-
-		nightly := liferay.Nightly{Tag: tagToPull}
-
-		pullDockerImage(nightly, forceRemoval)
-	},
-}
-
-var pullRelease = &cobra.Command{
-	Use:   "release",
-	Short: "Pulls a Liferay Portal Docker image from releases",
-	Long: `Pulls a Liferay Portal instance, obtained from the unofficial releases repository.
-	If no image tag is passed to the command, the "latest" tag will be used.`,
-	Args: func(cmd *cobra.Command, args []string) error {
-		if len(args) > 1 {
-			return errors.New("pull nightly requires zero or one argument representing the image tag to be pulled")
-		}
+// newPullCmd returns the subcommand pulling the image of a portal image type
+func newPullCmd(image liferay.Image) *cobra.Command {
+	subcommand := &cobra.Command{
+		Short: "Pulls a " + image.GetDescription() + " Docker image",
+		Long: `Pulls a ` + image.GetDescription() + ` Docker image, obtained from the [` + image.GetRepository() + `] repository.
+	` + getDefaultTagHelp(image),
+		Args: func(cmd *cobra.Command, args []string) error {
+			if len(args) > 1 {
+				return errors.New("pull requires zero or one argument representing the image tag to be pulled")
+			}
+
+			return nil
+		},
+		Run: func(cmd *cobra.Command, args []string) {
+			tagToPull = getImageTag(image, tagToPull, true)
+
+			imageToPull := newImage(image.GetType(), tagToPull)
+
+			pullDockerImage(newImage(image.GetType(), resolveTag(imageToPull)), forceRemoval)
+		},
+	}
 
-		return nil
-	},
-	Run: func(cmd *cobra.Command, args []string) {
-		if tagToPull == "" {
-			tagToPull = "latest"
-		}
+	subcommand.Flags().BoolVarP(&forceRemoval, "forceRemoval", "f", false, "Removes the cached, local image, if exists")
+	subcommand.Flags().StringVarP(&tagToPull, "tag", "t", "", "Sets the image tag to pull. Symbolic tags are resolved against the registry: [7.2.x|latest-ga|newest]")
 
-		release := liferay.Release{Tag: tagToPull}
+	if image.HasDateTags() {
+		addDateFlag(subcommand)
+	}
 
-		pullDockerImage(release, forceRemoval)
-	},
+	return subcommand
 }
 
 // pullDockerImage uses the image interface to pull it from Docker Hub, removing the cached on if
@@ -181,4 +84,6 @@ func pullDockerImage(image liferay.Image, forceRemoval bool) {
 	}
 
 	docker.PullDockerImage(image.GetFullyQualifiedName())
+
+	inspectImageSettings(image)
 }
@@ -0,0 +1,188 @@
+package cmd
+
+import (
+	"time"
+
+	docker "github.com/mdelapenya/lpn/docker"
+	liferay "github.com/mdelapenya/lpn/liferay"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+var restartDebug bool
+var restartDebugPort int
+var restartGC string
+var restartHeap string
+var restartMemory string
+var restartMetaspace string
+var restartProperties []string
+var restartTimeout time.Duration
+
+func init() {
+	rootCmd.AddCommand(restartCmd)
+
+	addPortalSubcommands(restartCmd, newRestartCmd)
+}
+
+var restartCmd = &cobra.Command{
+	Use:   "restart",
+	Short: "Restarts the Liferay Portal nook instance",
+	Long: `Restarts the Liferay Portal nook instance, identified by [lpn] plus each image type, and its stack.
+	For that, please run this command adding the image type as subcommand (see configuration file).`,
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		enableDebugLevel()
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		SubCommandInfo()
+	},
+	Annotations: map[string]string{containersAnnotation: containersAll},
+}
+
+// newRestartCmd returns the subcommand restarting the stack of a portal image type
+func newRestartCmd(image liferay.Image) *cobra.Command {
+	subcommand := &cobra.Command{
+		Short: "Restarts the " + image.GetDescription() + " instance",
+		Long: `Restarts the ` + image.GetDescription() + ` instance, identified by [` + image.GetContainerName() + `], and its stack.
+	The portal is stopped first and started last. If the memory, JVM, debug or property flags are passed, only the
+	portal container is recreated with the changes, keeping its image, database and ports.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			restartStack(image, cmd.Flags())
+		},
+	}
+
+	subcommand.Flags().BoolVarP(&restartDebug, "debug", "d", false, "Enables or disables debug mode, recreating the portal container")
+	subcommand.Flags().IntVarP(&restartDebugPort, "debugPort", "D", 9000, "Sets the debug port of Liferay Portal's bundle. It only applies if debug mode is enabled")
+	subcommand.Flags().StringVarP(&restartMemory, "memory", "m", "", "Sets the memory for the JVM memory configuration of Liferay Portal's bundle, recreating the portal container")
+	subcommand.Flags().StringVar(&restartHeap, "heap", "", "Sets the maximum heap of the JVM, as 4g, replacing the one of the current JVM options, recreating the portal container")
+	subcommand.Flags().StringVar(&restartMetaspace, "metaspace", "", "Sets the maximum metaspace of the JVM, as 512m, replacing the one of the current JVM options, recreating the portal container")
+	subcommand.Flags().StringVar(&restartGC, "gc", "", "Sets the garbage collector of the JVM, replacing the one of the current JVM options, recreating the portal container. Supported values are [g1|parallel]")
+	subcommand.Flags().StringArrayVarP(&restartProperties, "property", "P", []string{}, "Sets a portal property in the key=value format, recreating the portal container. It can be repeated")
+	subcommand.Flags().DurationVar(&restartTimeout, "timeout", docker.DefaultStopTimeout, "Sets the time the portal is given to shut down gracefully before it's killed")
+
+	return subcommand
+}
+
+// recreatePortal recreates the portal container of the image with the changes of the flags, keeping
+// the image, database and ports it was run with
+func recreatePortal(image liferay.Image, flags *pflag.FlagSet) {
+	settings, err := docker.GetRunSettings(image)
+	if err != nil {
+		log.WithFields(log.Fields{
+			"container": image.GetContainerName(),
+			"error":     err,
+		}).Fatal("Impossible to read the settings of the container. Please run it first")
+	}
+
+	image = newImage(image.GetType(), settings.Tag)
+	options := settings.RunOptions
+
+	if flags.Changed("memory") {
+		options.Memory = restartMemory
+	}
+
+	options.JVMOptions = buildJVMOptions(restartHeap, restartMetaspace, restartGC)
+
+	if flags.Changed("debug") {
+		options.EnableDebug = restartDebug
+
+		if options.DebugPort == 0 || flags.Changed("debugPort") {
+			options.DebugPort = restartDebugPort
+		}
+	}
+
+	options.Properties = liferay.MergeProperties(options.Properties, restartProperties)
+
+	checkMemLimit(options)
+
+	// the portal container is removed before running the new one, so the options are validated first
+	for _, property := range options.Properties {
+		_, err := liferay.GetPropertyEnvVariable(property)
+		if err != nil {
+			log.WithFields(log.Fields{
+				"property": property,
+				"error":    err,
+			}).Fatal("Invalid property")
+		}
+	}
+
+	if options.License != "" {
+		_, err := liferay.ReadLicense(options.License)
+		if err != nil {
+			log.WithFields(log.Fields{
+				"license": options.License,
+				"error":   err,
+			}).Fatal("Impossible to read the activation key the container was run with")
+		}
+	}
+
+	database := docker.GetDatabase(image, settings.Datastore)
+
+	err = docker.StopPortalContainer(image, restartTimeout)
+	if err != nil {
+		log.WithFields(log.Fields{
+			"container": image.GetContainerName(),
+			"error":     err,
+		}).Fatal("Impossible to stop the portal")
+	}
+
+	if database != nil && !docker.IsContainerRunning(database.GetContainerName()) {
+		err = docker.StartContainer(database.GetContainerName())
+		if err != nil {
+			log.WithFields(log.Fields{
+				"container": database.GetContainerName(),
+				"error":     err,
+			}).Fatal("Impossible to start the database")
+		}
+	}
+
+	err = docker.RecreatePortalContainer(image, database, options)
+	if err != nil {
+		log.WithFields(log.Fields{
+			"container": image.GetContainerName(),
+			"error":     err,
+		}).Fatal("Impossible to recreate the portal")
+	}
+
+	log.WithFields(log.Fields{
+		"container":  image.GetContainerName(),
+		"image":      image.GetFullyQualifiedName(),
+		"datastore":  settings.Datastore,
+		"debug":      options.EnableDebug,
+		"debugPort":  options.DebugPort,
+		"memory":     options.Memory,
+		"jvmOptions": options.JVMOptions,
+		"properties": options.Properties,
+	}).Info("The portal has been recreated")
+}
+
+// restartStack restarts the stack of the image, recreating the portal container if any of its
+// settings changes
+func restartStack(image liferay.Image, flags *pflag.FlagSet) {
+	if flags.Changed("memory") || flags.Changed("heap") || flags.Changed("metaspace") || flags.Changed("gc") ||
+		flags.Changed("debug") || flags.Changed("property") {
+		recreatePortal(image, flags)
+		return
+	}
+
+	err := docker.StopDockerContainer(image, restartTimeout)
+	if err != nil {
+		log.WithFields(log.Fields{
+			"container": image.GetContainerName(),
+			"error":     err,
+		}).Fatal("Impossible to stop the stack")
+	}
+
+	err = docker.StartDockerContainer(image)
+	if err != nil {
+		log.WithFields(log.Fields{
+			"container": image.GetContainerName(),
+			"error":     err,
+		}).Fatal("Impossible to start the stack")
+	}
+
+	log.WithFields(log.Fields{
+		"container": image.GetContainerName(),
+	}).Info("The stack has been restarted")
+}
@@ -11,16 +11,7 @@ import (
 func init() {
 	rootCmd.AddCommand(rmCmd)
 
-	subcommands := []*cobra.Command{rmCECmd, rmCommerceCmd, rmDXPCmd, rmNightlyCmd, rmReleaseCmd}
-
-	for i := 0; i < len(subcommands); i++ {
-		subcommand := subcommands[i]
-
-		rmCmd.AddCommand(subcommand)
-
-		subcommand.PersistentFlags().BoolVarP(&verbose, "verbose", "V", false, "Runs commands with Debug log level")
-		subcommand.VisitParents(addVerboseFlag)
-	}
+	addPortalSubcommands(rmCmd, newRmCmd)
 }
 
 var rmCmd = &cobra.Command{
@@ -33,61 +24,18 @@ var rmCmd = &cobra.Command{
 	Run: func(cmd *cobra.Command, args []string) {
 		SubCommandInfo()
 	},
+	Annotations: map[string]string{containersAnnotation: containersAll},
 }
 
-var rmCECmd = &cobra.Command{
-	Use:   "ce",
-	Short: "Removes the Liferay Portal CE instance",
-	Long:  `Removes the Liferay Portal CE instance, identified by [lpn-ce].`,
-	Run: func(cmd *cobra.Command, args []string) {
-		ce := liferay.CE{}
-
-		removeDockerContainer(ce)
-	},
-}
-
-var rmCommerceCmd = &cobra.Command{
-	Use:   "commerce",
-	Short: "Removes the Liferay Portal Commerce instance",
-	Long:  `Removes the Liferay Portal Commerce instance, identified by [lpn-commerce].`,
-	Run: func(cmd *cobra.Command, args []string) {
-		commerce := liferay.Commerce{}
-
-		removeDockerContainer(commerce)
-	},
-}
-
-var rmDXPCmd = &cobra.Command{
-	Use:   "dxp",
-	Short: "Removes the Liferay DXP instance",
-	Long:  `Removes the Liferay DXP instance, identified by [lpn-dxp].`,
-	Run: func(cmd *cobra.Command, args []string) {
-		dxp := liferay.DXP{}
-
-		removeDockerContainer(dxp)
-	},
-}
-
-var rmNightlyCmd = &cobra.Command{
-	Use:   "nightly",
-	Short: "Removes the Liferay Portal Nightly Build instance",
-	Long:  `Removes the Liferay Portal Nightly Build instance, identified by [lpn-nightly].`,
-	Run: func(cmd *cobra.Command, args []string) {
-		nightly := liferay.Nightly{}
-
-		removeDockerContainer(nightly)
-	},
-}
-
-var rmReleaseCmd = &cobra.Command{
-	Use:   "release",
-	Short: "Removes the Liferay Portal Release instance",
-	Long:  `Removes the Liferay Portal Release instance, identified by [lpn-release].`,
-	Run: func(cmd *cobra.Command, args []string) {
-		release := liferay.Release{}
-
-		removeDockerContainer(release)
-	},
+// newRmCmd returns the subcommand removing the container of a portal image type
+func newRmCmd(image liferay.Image) *cobra.Command {
+	return &cobra.Command{
+		Short: "Removes the " + image.GetDescription() + " instance",
+		Long:  `Removes the ` + image.GetDescription() + ` instance, identified by [` + image.GetContainerName() + `].`,
+		Run: func(cmd *cobra.Command, args []string) {
+			removeDockerContainer(image)
+		},
+	}
 }
 
 // removeDockerContainer removes the running container
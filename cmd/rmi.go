@@ -1,9 +1,7 @@
 package cmd
 
 import (
-	date "github.com/mdelapenya/lpn/date"
 	docker "github.com/mdelapenya/lpn/docker"
-	internal "github.com/mdelapenya/lpn/internal"
 	liferay "github.com/mdelapenya/lpn/liferay"
 
 	"github.com/spf13/cobra"
@@ -14,18 +12,7 @@ var tagToRemove string
 func init() {
 	rootCmd.AddCommand(rmiCmd)
 
-	subcommands := []*cobra.Command{rmiCECmd, rmiCommerceCmd, rmiDXPCmd, rmiNightlyCmd, rmiReleaseCmd}
-
-	for i := 0; i < len(subcommands); i++ {
-		subcommand := subcommands[i]
-
-		subcommand.Flags().StringVarP(&tagToRemove, "tag", "t", "", "Sets the image tag to remove")
-
-		rmiCmd.AddCommand(subcommand)
-
-		subcommand.PersistentFlags().BoolVarP(&verbose, "verbose", "V", false, "Runs commands with Debug log level")
-		subcommand.VisitParents(addVerboseFlag)
-	}
+	addPortalSubcommands(rmiCmd, newRmiCmd)
 }
 
 var rmiCmd = &cobra.Command{
@@ -40,79 +27,26 @@ var rmiCmd = &cobra.Command{
 	},
 }
 
-var rmiCECmd = &cobra.Command{
-	Use:   "ce",
-	Short: "Removes the Official Liferay Portal CE image",
-	Long:  `Removes the Official Liferay Portal CE image from the Docker host.`,
-	Run: func(cmd *cobra.Command, args []string) {
-		if tagToRemove == "" {
-			tagToRemove = internal.LpnConfig.GetPortalImageTag("ce")
-		}
-
-		ce := liferay.CE{Tag: tagToRemove}
-
-		removeDockerImage(ce)
-	},
-}
-
-var rmiCommerceCmd = &cobra.Command{
-	Use:   "commerce",
-	Short: "Removes the Liferay Portal Commerce image",
-	Long:  `Removes the Liferay Portal Commerce image from the Docker host.`,
-	Run: func(cmd *cobra.Command, args []string) {
-		if tagToRemove == "" {
-			tagToRemove = date.CurrentDate
-		}
-
-		commerce := liferay.Commerce{Tag: tagToRemove}
-
-		removeDockerImage(commerce)
-	},
-}
+// newRmiCmd returns the subcommand removing the image of a portal image type
+func newRmiCmd(image liferay.Image) *cobra.Command {
+	subcommand := &cobra.Command{
+		Short: "Removes the " + image.GetDescription() + " image",
+		Long: `Removes the ` + image.GetDescription() + ` image from the Docker host.
+	` + getDefaultTagHelp(image),
+		Run: func(cmd *cobra.Command, args []string) {
+			tagToRemove = getImageTag(image, tagToRemove, false)
 
-var rmiDXPCmd = &cobra.Command{
-	Use:   "dxp",
-	Short: "Removes the Official Liferay DXP image",
-	Long:  `Removes the Official Liferay DXP image from the Docker host.`,
-	Run: func(cmd *cobra.Command, args []string) {
-		if tagToRemove == "" {
-			tagToRemove = internal.LpnConfig.GetPortalImageTag("dxp")
-		}
-
-		dxp := liferay.DXP{Tag: tagToRemove}
-
-		removeDockerImage(dxp)
-	},
-}
-
-var rmiNightlyCmd = &cobra.Command{
-	Use:   "nightly",
-	Short: "Removes the Liferay Portal Nightly Build image",
-	Long:  `Removes the Liferay Portal Nightly Build image from the Docker host.`,
-	Run: func(cmd *cobra.Command, args []string) {
-		if tagToRemove == "" {
-			tagToRemove = date.CurrentDate
-		}
-
-		nightly := liferay.Nightly{Tag: tagToRemove}
+			removeDockerImage(newImage(image.GetType(), tagToRemove))
+		},
+	}
 
-		removeDockerImage(nightly)
-	},
-}
+	subcommand.Flags().StringVarP(&tagToRemove, "tag", "t", "", "Sets the image tag to remove")
 
-var rmiReleaseCmd = &cobra.Command{
-	Use:   "release",
-	Short: "Removes the Liferay Portal Release image",
-	Long:  `Removes the Liferay Portal Release image from the Docker host.`,
-	Run: func(cmd *cobra.Command, args []string) {
-		if tagToRemove == "" {
-			tagToRemove = "latest"
-		}
-
-		release := liferay.Release{Tag: tagToRemove}
+	if image.HasDateTags() {
+		addDateFlag(subcommand)
+	}
 
-		removeDockerImage(release)
-	},
+	return subcommand
 }
 
 // removeDockerImage removes the running container
@@ -1,13 +1,24 @@
 package cmd
 
 import (
+	"os"
+	"strings"
+
 	internal "github.com/mdelapenya/lpn/internal"
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 )
 
+var profile string
 var verbose bool
 
+func init() {
+	cobra.OnInitialize(configureOutput)
+
+	rootCmd.PersistentFlags().StringVarP(&outputFormat, "output", "o", outputTable, "Sets the output format of the command. Supported values are [table|json|yaml]")
+	rootCmd.PersistentFlags().StringVar(&profile, "profile", "", "Sets the profile to work with, overriding the LPN_PROFILE environment variable (default profile is 'default')")
+}
+
 var rootCmd = &cobra.Command{
 	Use:   "lpn",
 	Short: "lpn (Liferay Portal Nook) makes it easier to run Liferay Portal's Docker images.",
@@ -34,6 +45,21 @@ func enableDebugLevel() {
 
 // Execute execute root command
 func Execute() {
+	// the profile is selected before the subcommands are generated from its configuration
+	if p, ok := getProfileArg(os.Args[1:]); ok {
+		err := internal.UseProfile(p, createsProfile(os.Args[1:]))
+		if err != nil {
+			log.WithFields(log.Fields{
+				"profile": p,
+				"error":   err,
+			}).Fatal("Cannot use the profile")
+		}
+	}
+
+	generatePortalSubcommands()
+
+	checkConfig(os.Args[1:])
+
 	if err := rootCmd.Execute(); err != nil {
 		log.Fatal(err)
 	}
@@ -43,6 +69,53 @@ func Execute() {
 func SubCommandInfo() {
 	// delegate to subcommands
 	log.Warn(
-		"Please run this command adding 'ce', 'commerce', 'dxp', 'nightly' or 'release' " +
-			"subcommands.")
+		"Please run this command adding one of the following subcommands: " +
+			strings.Join(internal.LpnConfig.GetPortalTypes(), ", "))
+}
+
+// createsProfile returns if the command creates the profile it's run with, when it does not exist.
+// Only running a stack does, so that the rest of commands fail on a mistyped profile
+func createsProfile(args []string) bool {
+	cmd, _, err := rootCmd.Find(args)
+	if err != nil {
+		return false
+	}
+
+	for c := cmd; c != nil; c = c.Parent() {
+		if c == runCmd {
+			return true
+		}
+	}
+
+	return false
+}
+
+// getProfileArg returns the value of the profile flag, as --profile customer or --profile=customer,
+// which is read before parsing the flags, or the one of the LPN_PROFILE environment variable. The
+// flags of the command lines being completed are not taken into account, as the profile could be
+// being typed
+func getProfileArg(args []string) (string, bool) {
+	if len(args) > 0 && args[0] == completeCommand {
+		args = []string{}
+	}
+
+	for i, arg := range args {
+		if arg == "--" {
+			break
+		}
+
+		if strings.HasPrefix(arg, "--profile=") {
+			return strings.TrimPrefix(arg, "--profile="), true
+		}
+
+		if arg == "--profile" && i+1 < len(args) {
+			return args[i+1], true
+		}
+	}
+
+	if p := os.Getenv("LPN_PROFILE"); p != "" {
+		return p, true
+	}
+
+	return "", false
 }
@@ -2,8 +2,9 @@ package cmd
 
 import (
 	"errors"
+	"os"
+	"path/filepath"
 
-	date "github.com/mdelapenya/lpn/date"
 	docker "github.com/mdelapenya/lpn/docker"
 	internal "github.com/mdelapenya/lpn/internal"
 	liferay "github.com/mdelapenya/lpn/liferay"
@@ -13,35 +14,29 @@ import (
 )
 
 var enableDebug bool
+var cpus float64
 var datastore string
+var dbEnv []string
+var dbMemLimit string
 var debugPort int
+var env []string
+var envFiles []string
+var gc string
 var gogoPort int
+var heap string
+var hotDeploy string
 var httpPort int
+var licenseToRun string
+var memLimit string
 var memory string
+var metaspace string
+var mounts []string
 var tagToRun string
 
 func init() {
 	rootCmd.AddCommand(runCmd)
 
-	subcommands := []*cobra.Command{
-		runCECmd, runCommerceCmd, runDXPCmd, runNightlyCmd, runReleaseCmd}
-
-	for i := 0; i < len(subcommands); i++ {
-		subcommand := subcommands[i]
-
-		runCmd.AddCommand(subcommand)
-
-		subcommand.Flags().IntVarP(&httpPort, "httpPort", "p", 8080, "Sets the HTTP port of Liferay Portal's bundle.")
-		subcommand.Flags().BoolVarP(&enableDebug, "debug", "d", false, "Enables debug mode. (default false)")
-		subcommand.Flags().IntVarP(&debugPort, "debugPort", "D", 9000, "Sets the debug port of Liferay Portal's bundle. It only applies if debug mode is enabled")
-		subcommand.Flags().IntVarP(&gogoPort, "gogoPort", "g", 11311, "Sets the GoGo Shell port of Liferay Portal's bundle.")
-		subcommand.Flags().StringVarP(&datastore, "datastore", "s", "hsql", "Creates a database service for the running instance. Supported values are [hsql|mysql|postgresql] (default HSQL)")
-		subcommand.Flags().StringVarP(&tagToRun, "tag", "t", "", "Sets the image tag to run")
-		subcommand.Flags().StringVarP(&memory, "memory", "m", "", "Sets the memory for the JVM memory configuration of Liferay Portal's bundle.")
-
-		subcommand.PersistentFlags().BoolVarP(&verbose, "verbose", "V", false, "Runs commands with Debug log level")
-		subcommand.VisitParents(addVerboseFlag)
-	}
+	addPortalSubcommands(runCmd, newRunCmd)
 }
 
 var runCmd = &cobra.Command{
@@ -49,7 +44,7 @@ var runCmd = &cobra.Command{
 	Short: "Runs a Liferay Portal instance",
 	Long: `Runs a Liferay Portal instance, obtained from the Official repositories (see configuration file).
 		For non-official Docker images, the tool runs images obtained from the unofficial repositories (see configuration file).
-	For that, please run this command adding "ce", "commerce", "dxp", "release" or "nightly" subcommands.`,
+	For that, please run this command adding the image type as subcommand (see configuration file).`,
 	Args: func(cmd *cobra.Command, args []string) error {
 		if len(args) > 1 {
 			return errors.New("run requires zero or one argument representing the image tag to be run")
@@ -65,134 +60,225 @@ var runCmd = &cobra.Command{
 	},
 }
 
-var runCECmd = &cobra.Command{
-	Use:   "ce",
-	Short: "Runs a Liferay Portal CE instance",
-	Long: `Runs a Liferay Portal CE instance, obtained from the Official repository.
-	If no image tag is passed to the command, the default tag (see configuration file) will be used.`,
-	Args: func(cmd *cobra.Command, args []string) error {
-		if len(args) > 1 {
-			return errors.New("run requires zero or one argument representing the image tag to be run")
-		}
+// newRunCmd returns the subcommand running a container of a portal image type
+func newRunCmd(image liferay.Image) *cobra.Command {
+	subcommand := &cobra.Command{
+		Short: "Runs a " + image.GetDescription() + " instance",
+		Long: `Runs a ` + image.GetDescription() + ` instance, obtained from the [` + image.GetRepository() + `] repository.
+	` + getDefaultTagHelp(image),
+		Args: func(cmd *cobra.Command, args []string) error {
+			if len(args) > 1 {
+				return errors.New("run requires zero or one argument representing the image tag to be run")
+			}
+
+			return nil
+		},
+		Run: func(cmd *cobra.Command, args []string) {
+			tagToRun = getImageTag(image, tagToRun, true)
+
+			imageToRun := newImage(image.GetType(), tagToRun)
+
+			if licenseToRun != "" {
+				licenseToRun = checkLicense(licenseToRun, licenseExpirationDays)
+			}
+
+			options := docker.RunOptions{
+				CPUs:          cpus,
+				DbEnv:         buildEnvVariables(nil, dbEnv),
+				DbMemLimit:    parseMemLimit(dbMemLimit),
+				DebugPort:     debugPort,
+				EnableDebug:   enableDebug,
+				Env:           buildEnvVariables(envFiles, env),
+				GogoShellPort: gogoPort,
+				HotDeploy:     getHotDeployDir(hotDeploy),
+				HTTPPort:      httpPort,
+				JVMOptions:    buildJVMOptions(heap, metaspace, gc),
+				License:       licenseToRun,
+				MemLimit:      parseMemLimit(memLimit),
+				Memory:        memory,
+				Mounts:        buildMounts(mounts),
+			}
+
+			checkMemLimit(options)
+
+			runLiferayDockerImage(newImage(image.GetType(), resolveTag(imageToRun)), datastore, options)
+		},
+	}
 
-		return nil
-	},
-	Run: func(cmd *cobra.Command, args []string) {
-		if tagToRun == "" {
-			tagToRun = internal.LpnConfig.GetPortalImageTag("ce")
-		}
+	subcommand.Flags().IntVarP(&httpPort, "httpPort", "p", 8080, "Sets the HTTP port of Liferay Portal's bundle.")
+	subcommand.Flags().BoolVarP(&enableDebug, "debug", "d", false, "Enables debug mode. (default false)")
+	subcommand.Flags().IntVarP(&debugPort, "debugPort", "D", 9000, "Sets the debug port of Liferay Portal's bundle. It only applies if debug mode is enabled")
+	subcommand.Flags().IntVarP(&gogoPort, "gogoPort", "g", 11311, "Sets the GoGo Shell port of Liferay Portal's bundle.")
+	subcommand.Flags().StringVarP(&datastore, "datastore", "s", "hsql", "Creates a database service for the running instance. Supported values are [hsql|mysql|postgresql] (default HSQL)")
+	subcommand.Flags().StringVarP(&tagToRun, "tag", "t", "", "Sets the image tag to run. Symbolic tags are resolved against the registry: [7.2.x|latest-ga|newest]")
+	subcommand.Flags().StringVarP(&memory, "memory", "m", "", "Sets the memory for the JVM memory configuration of Liferay Portal's bundle, replacing the JVM options of the image as they are.")
+	subcommand.Flags().StringVar(&heap, "heap", "", "Sets the maximum heap of the JVM, as 4g, appended to the JVM options of the image")
+	subcommand.Flags().StringVar(&metaspace, "metaspace", "", "Sets the maximum metaspace of the JVM, as 512m, appended to the JVM options of the image")
+	subcommand.Flags().StringVar(&gc, "gc", "", "Sets the garbage collector of the JVM, appended to the JVM options of the image. Supported values are [g1|parallel]")
+	subcommand.Flags().Float64Var(&cpus, "cpus", internal.LpnConfig.Resources.CPUs, "Sets the number of CPUs the portal container can use, as 1.5. Zero does not limit them")
+	subcommand.Flags().StringVar(&memLimit, "mem-limit", internal.LpnConfig.Resources.MemLimit, "Sets the memory limit of the portal container, as 4g. Empty does not limit it")
+	subcommand.Flags().StringArrayVarP(&env, "env", "e", []string{}, "Sets an environment variable of the portal container in the KEY=VALUE format, overriding the ones set by lpn. It can be repeated")
+	subcommand.Flags().StringArrayVar(&envFiles, "env-file", []string{}, "Reads the environment variables of the portal container from a file, with a KEY=VALUE variable per line. It can be repeated")
+	subcommand.Flags().StringArrayVar(&dbEnv, "db-env", []string{}, "Sets an environment variable of the database container in the KEY=VALUE format, overriding the ones set by lpn. It can be repeated")
+	subcommand.Flags().StringArrayVar(&mounts, "mount", []string{}, "Mounts a local directory or file into the portal container in the host:container[:ro] format. Relative container paths are resolved against Liferay Home. It can be repeated")
+	subcommand.Flags().StringVar(&hotDeploy, "hot-deploy", "", "Mounts a local directory onto the deploy folder of the portal container, so the files written into it are deployed")
+	subcommand.Flags().StringVar(&dbMemLimit, "db-mem-limit", internal.LpnConfig.Resources.DbMemLimit, "Sets the memory limit of the database container, as 1g. Empty does not limit it")
+
+	if image.HasDateTags() {
+		addDateFlag(subcommand)
+	}
 
-		ce := liferay.CE{Tag: tagToRun}
+	if image.RequiresLicense() {
+		subcommand.Flags().StringVarP(&licenseToRun, "license", "l", "", "Sets the XML file of the activation key to install into the instance before it boots.")
+	}
 
-		runLiferayDockerImage(ce, datastore, httpPort, gogoPort, enableDebug, debugPort, memory)
-	},
+	return subcommand
 }
 
-var runCommerceCmd = &cobra.Command{
-	Use:   "commerce",
-	Short: "Runs a Liferay Portal with Commerce instance from Commerce Builds",
-	Long: `Runs a Liferay Portal with Commerce instance, obtained from Commerce Builds repository.
-	If no image tag is passed to the command, the default tag (see configuration file) will be used.`,
-	Args: func(cmd *cobra.Command, args []string) error {
-		if len(args) > 1 {
-			return errors.New("run requires zero or one argument representing the image tag to be run")
-		}
+// checkMemLimit warns if the maximum heap of the JVM options exceeds the memory limit of the
+// portal container, which would be killed when the heap grows
+func checkMemLimit(options docker.RunOptions) {
+	if options.MemLimit <= 0 {
+		return
+	}
 
-		return nil
-	},
-	Run: func(cmd *cobra.Command, args []string) {
-		if tagToRun == "" {
-			tagToRun = internal.LpnConfig.GetPortalImageTag("commerce")
-		}
+	jvmOptions := liferay.AppendJVMOptions(options.Memory, options.JVMOptions)
 
-		commerce := liferay.Commerce{Tag: tagToRun}
+	maxHeap, ok := liferay.GetMaxHeap(jvmOptions)
+	if !ok || maxHeap <= options.MemLimit {
+		return
+	}
 
-		runLiferayDockerImage(
-			commerce, datastore, httpPort, gogoPort, enableDebug, debugPort, memory)
-	},
+	log.WithFields(log.Fields{
+		"jvmOptions": jvmOptions,
+		"heap":       maxHeap,
+		"memLimit":   options.MemLimit,
+	}).Warn("The maximum heap of the JVM exceeds the memory limit of the container, which could be killed")
 }
 
-var runDXPCmd = &cobra.Command{
-	Use:   "dxp",
-	Short: "Runs a Liferay DXP instance",
-	Long: `Runs a Liferay DXP instance, obtained from the Official repository, including a 30-day activation key.
-	If no image tag is passed to the command, the default tag (see configuration file) will be used.`,
-	Args: func(cmd *cobra.Command, args []string) error {
-		if len(args) > 1 {
-			return errors.New("run requires zero or one argument representing the image tag to be run")
+// buildEnvVariables returns the environment variables of the files, in order, and then the ones of
+// the variables passed, so the latter override the former
+func buildEnvVariables(files []string, variables []string) []string {
+	result := []string{}
+
+	for _, file := range files {
+		fileVariables, err := liferay.ReadEnvFile(file)
+		if err != nil {
+			log.WithFields(log.Fields{
+				"file":  file,
+				"error": err,
+			}).Fatal("Impossible to read the environment variables file")
 		}
 
-		return nil
-	},
-	Run: func(cmd *cobra.Command, args []string) {
-		if tagToRun == "" {
-			tagToRun = internal.LpnConfig.GetPortalImageTag("dxp")
+		result = liferay.MergeEnvVariables(result, fileVariables)
+	}
+
+	for _, variable := range variables {
+		envVariable, err := liferay.ParseEnvVariable(variable)
+		if err != nil {
+			log.WithFields(log.Fields{
+				"variable": variable,
+				"error":    err,
+			}).Fatal("Invalid environment variable")
 		}
 
-		dxp := liferay.DXP{Tag: tagToRun}
+		result = liferay.MergeEnvVariables(result, []string{envVariable})
+	}
 
-		runLiferayDockerImage(dxp, datastore, httpPort, gogoPort, enableDebug, debugPort, memory)
-	},
+	return result
 }
 
-var runNightlyCmd = &cobra.Command{
-	Use:   "nightly",
-	Short: "Runs a Liferay Portal instance from Nightly Builds",
-	Long: `Runs a Liferay Portal instance, obtained from Nightly Builds repository.
-	If no image tag is passed to the command, the tag representing the current date [` + date.CurrentDate + `] will be used.`,
-	Args: func(cmd *cobra.Command, args []string) error {
-		if len(args) > 1 {
-			return errors.New("run requires zero or one argument representing the image tag to be run")
+// buildMounts returns the bind mounts of the mount flags, checking that their local paths exist
+func buildMounts(specs []string) []liferay.Mount {
+	result := []liferay.Mount{}
+
+	for _, spec := range specs {
+		mount, err := liferay.ParseMount(spec)
+		if err != nil {
+			log.WithFields(log.Fields{
+				"mount": spec,
+				"error": err,
+			}).Fatal("Invalid mount")
 		}
 
-		return nil
-	},
-	Run: func(cmd *cobra.Command, args []string) {
-		if tagToRun == "" {
-			tagToRun = date.CurrentDate
+		if _, err := os.Stat(mount.Source); err != nil {
+			log.WithFields(log.Fields{
+				"mount": spec,
+				"error": err,
+			}).Fatal("The local path of the mount does not exist")
 		}
 
-		nightly := liferay.Nightly{Tag: tagToRun}
+		result = append(result, mount)
+	}
 
-		runLiferayDockerImage(
-			nightly, datastore, httpPort, gogoPort, enableDebug, debugPort, memory)
-	},
+	return result
 }
 
-var runReleaseCmd = &cobra.Command{
-	Use:   "release",
-	Short: "Runs a Liferay Portal instance from releases",
-	Long: `Runs a Liferay Portal instance, obtained from the unofficial releases repository.
-	If no image tag is passed to the command, the "latest" tag will be used.`,
-	Args: func(cmd *cobra.Command, args []string) error {
-		if len(args) > 1 {
-			return errors.New("run requires zero or one argument representing the image tag to be run")
-		}
+// getHotDeployDir returns the absolute path of the hot deploy directory, creating it if it does not
+// exist, or empty if no directory is passed
+func getHotDeployDir(dir string) string {
+	if dir == "" {
+		return ""
+	}
 
-		return nil
-	},
-	Run: func(cmd *cobra.Command, args []string) {
-		if tagToRun == "" {
-			tagToRun = "latest"
-		}
+	absDir, err := filepath.Abs(dir)
+	if err == nil {
+		err = os.MkdirAll(absDir, os.ModePerm)
+	}
 
-		release := liferay.Release{Tag: tagToRun}
+	if err != nil {
+		log.WithFields(log.Fields{
+			"dir":   dir,
+			"error": err,
+		}).Fatal("Impossible to create the hot deploy directory")
+	}
 
-		runLiferayDockerImage(
-			release, datastore, httpPort, gogoPort, enableDebug, debugPort, memory)
-	},
+	return absDir
+}
+
+// buildJVMOptions returns the JVM options of the heap, metaspace and garbage collector flags
+func buildJVMOptions(heap string, metaspace string, gc string) string {
+	jvmOptions, err := liferay.JVMOptions{
+		GC:        gc,
+		Heap:      heap,
+		Metaspace: metaspace,
+	}.Build()
+	if err != nil {
+		log.WithFields(log.Fields{
+			"heap":      heap,
+			"metaspace": metaspace,
+			"gc":        gc,
+			"error":     err,
+		}).Fatal("Invalid JVM options")
+	}
+
+	return jvmOptions
+}
+
+// parseMemLimit returns the bytes of a memory limit, or zero if it's empty
+func parseMemLimit(limit string) int64 {
+	if limit == "" {
+		return 0
+	}
+
+	bytes, err := liferay.ParseMemorySize(limit)
+	if err != nil {
+		log.WithFields(log.Fields{
+			"limit": limit,
+			"error": err,
+		}).Fatal("Invalid memory limit")
+	}
+
+	return bytes
 }
 
 // runLiferayDockerImage runs the Liferay image, potentially with a datastore
-func runLiferayDockerImage(
-	image liferay.Image, datastore string, httpPort int, gogoPort int, enableDebug bool,
-	debugPort int, memory string) {
+func runLiferayDockerImage(image liferay.Image, datastore string, options docker.RunOptions) {
 
 	if datastore != "hsql" {
 		database := docker.GetDatabase(image, datastore)
 
-		err := docker.RunLiferayDockerImage(
-			image, database, httpPort, gogoPort, enableDebug, debugPort, memory)
+		err := docker.RunLiferayDockerImage(image, database, options)
 
 		if err != nil {
 			log.WithFields(log.Fields{
@@ -205,15 +291,15 @@ func runLiferayDockerImage(
 			"container": image.GetContainerName(),
 			"image":     image.GetFullyQualifiedName(),
 			"datastore": datastore,
-			"httpPort":  httpPort,
-			"gogoPort":  gogoPort,
-			"debug":     enableDebug,
-			"debugPort": debugPort,
-			"memory":    memory,
+			"httpPort":  options.HTTPPort,
+			"gogoPort":  options.GogoShellPort,
+			"debug":     options.EnableDebug,
+			"debugPort": options.DebugPort,
+			"memory":    options.Memory,
+			"license":   options.License,
 		}).Info("The stack has been run successfully")
 	} else {
-		err := docker.RunLiferayDockerImage(
-			image, nil, httpPort, gogoPort, enableDebug, debugPort, memory)
+		err := docker.RunLiferayDockerImage(image, nil, options)
 
 		if err != nil {
 			log.WithFields(log.Fields{
@@ -226,11 +312,12 @@ func runLiferayDockerImage(
 			"container": image.GetContainerName(),
 			"image":     image.GetFullyQualifiedName(),
 			"datastore": datastore,
-			"httpPort":  httpPort,
-			"gogoPort":  gogoPort,
-			"debug":     enableDebug,
-			"debugPort": debugPort,
-			"memory":    memory,
+			"httpPort":  options.HTTPPort,
+			"gogoPort":  options.GogoShellPort,
+			"debug":     options.EnableDebug,
+			"debugPort": options.DebugPort,
+			"memory":    options.Memory,
+			"license":   options.License,
 		}).Info("The container has been run successfully")
 	}
 }
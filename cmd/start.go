@@ -11,17 +11,7 @@ import (
 func init() {
 	rootCmd.AddCommand(startCmd)
 
-	subcommands := []*cobra.Command{
-		startCECmd, startCommerceCmd, startDXPCmd, startNightlyCmd, startReleaseCmd}
-
-	for i := 0; i < len(subcommands); i++ {
-		subcommand := subcommands[i]
-
-		startCmd.AddCommand(subcommand)
-
-		subcommand.PersistentFlags().BoolVarP(&verbose, "verbose", "V", false, "Runs commands with Debug log level")
-		subcommand.VisitParents(addVerboseFlag)
-	}
+	addPortalSubcommands(startCmd, newStartCmd)
 }
 
 var startCmd = &cobra.Command{
@@ -34,61 +24,18 @@ var startCmd = &cobra.Command{
 	Run: func(cmd *cobra.Command, args []string) {
 		SubCommandInfo()
 	},
+	Annotations: map[string]string{containersAnnotation: containersAll},
 }
 
-var startCECmd = &cobra.Command{
-	Use:   "ce",
-	Short: "Starts the Liferay Portal CE instance",
-	Long:  `Starts the Liferay Portal CE instance, identified by [lpn-ce].`,
-	Run: func(cmd *cobra.Command, args []string) {
-		ce := liferay.CE{}
-
-		startDockerContainer(ce)
-	},
-}
-
-var startCommerceCmd = &cobra.Command{
-	Use:   "commerce",
-	Short: "Starts the Liferay Portal Commerce instance",
-	Long:  `Starts the Liferay Portal Commerce instance, identified by [lpn-commerce].`,
-	Run: func(cmd *cobra.Command, args []string) {
-		commerce := liferay.Commerce{}
-
-		startDockerContainer(commerce)
-	},
-}
-
-var startDXPCmd = &cobra.Command{
-	Use:   "dxp",
-	Short: "Starts the Liferay DXP instance",
-	Long:  `Starts the Liferay DXP instance, identified by [lpn-dxp].`,
-	Run: func(cmd *cobra.Command, args []string) {
-		dxp := liferay.DXP{}
-
-		startDockerContainer(dxp)
-	},
-}
-
-var startNightlyCmd = &cobra.Command{
-	Use:   "nightly",
-	Short: "Starts the Liferay Portal Nightly Build instance",
-	Long:  `Starts the Liferay Portal Nightly Build instance, identified by [lpn-nightly].`,
-	Run: func(cmd *cobra.Command, args []string) {
-		nightly := liferay.Nightly{}
-
-		startDockerContainer(nightly)
-	},
-}
-
-var startReleaseCmd = &cobra.Command{
-	Use:   "release",
-	Short: "Starts the Liferay Portal Release instance",
-	Long:  `Starts the Liferay Portal Release instance, identified by [lpn-release].`,
-	Run: func(cmd *cobra.Command, args []string) {
-		release := liferay.Release{}
-
-		startDockerContainer(release)
-	},
+// newStartCmd returns the subcommand starting the container of a portal image type
+func newStartCmd(image liferay.Image) *cobra.Command {
+	return &cobra.Command{
+		Short: "Starts the " + image.GetDescription() + " instance",
+		Long:  `Starts the ` + image.GetDescription() + ` instance, identified by [` + image.GetContainerName() + `].`,
+		Run: func(cmd *cobra.Command, args []string) {
+			startDockerContainer(image)
+		},
+	}
 }
 
 // startDockerContainer starts the stopped container
@@ -1,6 +1,8 @@
 package cmd
 
 import (
+	"time"
+
 	docker "github.com/mdelapenya/lpn/docker"
 	liferay "github.com/mdelapenya/lpn/liferay"
 
@@ -8,95 +10,52 @@ import (
 	"github.com/spf13/cobra"
 )
 
+var stopTimeout time.Duration
+
 func init() {
 	rootCmd.AddCommand(stopCmd)
 
-	subcommands := []*cobra.Command{
-		stopCECmd, stopCommerceCmd, stopDXPCmd, stopNightlyCmd, stopReleaseCmd}
-
-	for i := 0; i < len(subcommands); i++ {
-		subcommand := subcommands[i]
-
-		stopCmd.AddCommand(subcommand)
-
-		subcommand.PersistentFlags().BoolVarP(&verbose, "verbose", "V", false, "Runs commands with Debug log level")
-		subcommand.VisitParents(addVerboseFlag)
-	}
+	addPortalSubcommands(stopCmd, newStopCmd)
 }
 
 var stopCmd = &cobra.Command{
 	Use:   "stop",
 	Short: "Stops the Liferay Portal nook instance",
-	Long:  `Stops the Liferay Portal nook instance, identified by [lpn] plus each image type.`,
+	Long: `Stops the Liferay Portal nook instance, identified by [lpn] plus each image type.
+	The portal is stopped first, giving it time to shut down gracefully, and then the rest of its stack,
+	as the database.`,
 	PersistentPreRun: func(cmd *cobra.Command, args []string) {
 		enableDebugLevel()
 	},
 	Run: func(cmd *cobra.Command, args []string) {
 		SubCommandInfo()
 	},
-}
-
-var stopCECmd = &cobra.Command{
-	Use:   "ce",
-	Short: "Stops the Liferay Portal CE instance",
-	Long:  `Stops the Liferay Portal CE instance, identified by [lpn-ce].`,
-	Run: func(cmd *cobra.Command, args []string) {
-		ce := liferay.CE{}
-
-		stopDockerContainer(ce)
-	},
-}
-
-var stopCommerceCmd = &cobra.Command{
-	Use:   "commerce",
-	Short: "Stops the Liferay Portal Commerce instance",
-	Long:  `Stops the Liferay Portal Commerce instance, identified by [lpn-commerce].`,
-	Run: func(cmd *cobra.Command, args []string) {
-		commerce := liferay.Commerce{}
-
-		stopDockerContainer(commerce)
-	},
-}
-
-var stopDXPCmd = &cobra.Command{
-	Use:   "dxp",
-	Short: "Stops the Liferay DXP instance",
-	Long:  `Stops the Liferay DXP instance, identified by [lpn-dxp].`,
-	Run: func(cmd *cobra.Command, args []string) {
-		dxp := liferay.DXP{}
-
-		stopDockerContainer(dxp)
-	},
-}
-
-var stopNightlyCmd = &cobra.Command{
-	Use:   "nightly",
-	Short: "Stops the Liferay Portal Nightly Build instance",
-	Long:  `Stops the Liferay Portal Nightly Build instance, identified by [lpn-nightly].`,
-	Run: func(cmd *cobra.Command, args []string) {
-		nightly := liferay.Nightly{}
-
-		stopDockerContainer(nightly)
-	},
-}
+	Annotations: map[string]string{containersAnnotation: containersRunning},
+}
+
+// newStopCmd returns the subcommand stopping the container of a portal image type
+func newStopCmd(image liferay.Image) *cobra.Command {
+	subcommand := &cobra.Command{
+		Short: "Stops the " + image.GetDescription() + " instance",
+		Long: `Stops the ` + image.GetDescription() + ` instance, identified by [` + image.GetContainerName() + `], and then the rest
+	of its stack, as the database.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			stopDockerContainer(image, stopTimeout)
+		},
+	}
 
-var stopReleaseCmd = &cobra.Command{
-	Use:   "release",
-	Short: "Stops the Liferay Portal Release instance",
-	Long:  `Stops the Liferay Portal Release instance, identified by [lpn-release].`,
-	Run: func(cmd *cobra.Command, args []string) {
-		release := liferay.Release{}
+	subcommand.Flags().DurationVar(&stopTimeout, "timeout", docker.DefaultStopTimeout, "Sets the time the portal is given to shut down gracefully before it's killed")
 
-		stopDockerContainer(release)
-	},
+	return subcommand
 }
 
-// stopDockerContainer stops the running container
-func stopDockerContainer(image liferay.Image) {
-	err := docker.StopDockerContainer(image)
+// stopDockerContainer stops the running container, and then its stack
+func stopDockerContainer(image liferay.Image, timeout time.Duration) {
+	err := docker.StopDockerContainer(image, timeout)
 	if err != nil {
 		log.WithFields(log.Fields{
 			"container": image.GetContainerName(),
+			"error":     err,
 		}).Warn("Impossible to stop the container")
 	}
 }
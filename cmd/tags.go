@@ -1,14 +1,14 @@
 package cmd
 
 import (
-	"encoding/json"
 	"errors"
 	"fmt"
 	"math"
-	"net/http"
 	"os"
+	"strings"
 
 	liferay "github.com/mdelapenya/lpn/liferay"
+	registry "github.com/mdelapenya/lpn/registry"
 	tablewriter "github.com/olekukonko/tablewriter"
 
 	log "github.com/sirupsen/logrus"
@@ -17,54 +17,16 @@ import (
 
 var imagesSize int
 var imagesPage int
-
-type imageResponse struct {
-	Size         int
-	Architecture string
-	Variant      string
-	Features     string
-	OS           string
-	OSVersion    string
-	OSFeatures   string
-}
-
-type resultResponse struct {
-	Name        string
-	FullSize    int
-	Images      []imageResponse
-	ID          int64
-	Repository  int64
-	Creator     int64
-	LastUpdater int64
-	LastUpdated string
-	ImageID     string
-	V2          bool
-}
-
-type tagsResponse struct {
-	Count    int
-	Next     string
-	Previous string
-	Results  []resultResponse
-}
+var tagsAll bool
+var tagsFilter string
+var tagsRefresh bool
+var tagsSemver string
+var tagsSort string
 
 func init() {
 	rootCmd.AddCommand(tagsCmd)
 
-	subcommands := []*cobra.Command{
-		tagsCECmd, tagsCommerceCmd, tagsDXPCmd, tagsNightlyCmd, tagsReleaseCmd}
-
-	for i := 0; i < len(subcommands); i++ {
-		subcommand := subcommands[i]
-
-		subcommand.Flags().IntVarP(&imagesSize, "size", "s", 25, "Sets the number of tags to retrieve.")
-		subcommand.Flags().IntVarP(&imagesPage, "page", "p", 1, "Sets the page element where tags exist.")
-
-		tagsCmd.AddCommand(subcommand)
-
-		subcommand.PersistentFlags().BoolVarP(&verbose, "verbose", "V", false, "Runs commands with Debug log level")
-		subcommand.VisitParents(addVerboseFlag)
-	}
+	addPortalSubcommands(tagsCmd, newTagsCmd)
 }
 
 var tagsCmd = &cobra.Command{
@@ -72,7 +34,7 @@ var tagsCmd = &cobra.Command{
 	Short: "Lists all tags for Liferay Portal Docker image",
 	Long: `Lists all tags for Liferay Portal Docker image from the Official repositories (see configuration file).
 		For non-official Docker images, the tool lists tags from the unofficial repositories (see configuration file).
-	For that, please run this command adding "commerce", "release" or "nightly" subcommands.`,
+	For that, please run this command adding the image type as subcommand.`,
 	Args: func(cmd *cobra.Command, args []string) error {
 		if len(args) > 1 {
 			return errors.New("tags requires zero or one argument representing the image tag to be pulled")
@@ -88,70 +50,42 @@ var tagsCmd = &cobra.Command{
 	},
 }
 
-var tagsCECmd = &cobra.Command{
-	Use:   "ce",
-	Short: "Lists all tags for Liferay Portal CE Docker image",
-	Long:  `Lists all tags for Liferay Portal CE Docker image from the Official repository`,
-	Run: func(cmd *cobra.Command, args []string) {
-		ce := liferay.CE{}
-
-		readTags(ce, imagesSize, imagesPage)
-	},
-}
-
-var tagsCommerceCmd = &cobra.Command{
-	Use:   "commerce",
-	Short: "Lists all tags for Liferay Commerce Docker image",
-	Long:  `Lists all tags for Liferay Commerce Docker image from the Official repository`,
-	Run: func(cmd *cobra.Command, args []string) {
-		commerce := liferay.Commerce{}
-
-		readTags(commerce, imagesSize, imagesPage)
-	},
-}
-
-var tagsDXPCmd = &cobra.Command{
-	Use:   "dxp",
-	Short: "Lists all tags for Liferay DXP Docker image",
-	Long:  `Lists all tags for Liferay DXP Docker image from the Official repository`,
-	Run: func(cmd *cobra.Command, args []string) {
-		dxp := liferay.DXP{}
-
-		readTags(dxp, imagesSize, imagesPage)
-	},
-}
-
-var tagsNightlyCmd = &cobra.Command{
-	Use:   "nightly",
-	Short: "Lists all tags for Liferay Portal Nightly Build Docker image",
-	Long:  `Lists all tags for Liferay Portal Nightly Build Docker image from one of the unofficial repository`,
-	Run: func(cmd *cobra.Command, args []string) {
-		nightly := liferay.Nightly{}
-
-		readTags(nightly, imagesSize, imagesPage)
-	},
-}
+// newTagsCmd returns the subcommand listing the tags of a portal image type
+func newTagsCmd(image liferay.Image) *cobra.Command {
+	subcommand := &cobra.Command{
+		Short: "Lists all tags for " + image.GetDescription() + " Docker image",
+		Long:  `Lists all tags for ` + image.GetDescription() + ` Docker image from the [` + image.GetRepository() + `] repository`,
+		Run: func(cmd *cobra.Command, args []string) {
+			readTags(image, imagesSize, imagesPage)
+		},
+	}
 
-var tagsReleaseCmd = &cobra.Command{
-	Use:   "release",
-	Short: "Lists all tags for Liferay Portal Release Docker image",
-	Long:  `Lists all tags for Liferay Portal Release Docker image from one of the unofficial repository`,
-	Run: func(cmd *cobra.Command, args []string) {
-		release := liferay.Release{}
+	subcommand.Flags().IntVarP(&imagesSize, "size", "s", 25, "Sets the number of tags to retrieve.")
+	subcommand.Flags().IntVarP(&imagesPage, "page", "p", 1, "Sets the page element where tags exist.")
+	subcommand.Flags().BoolVarP(&tagsAll, "all", "a", false, "Retrieves all tags, following the next pages. The page flag is ignored")
+	subcommand.Flags().BoolVar(&tagsRefresh, "refresh", false, "Bypasses the tags cache, reading the tags from the registry")
+	subcommand.Flags().StringVarP(&tagsFilter, "filter", "f", "", "Filters the tags by a regular expression, reading all the pages")
+	subcommand.Flags().StringVar(&tagsSemver, "semver", "", "Filters the tags by a semver range, as '>=7.2.0 <7.3', reading all the pages")
+	subcommand.Flags().StringVar(&tagsSort, "sort", "", "Sorts the tags, reading all the pages. Supported values are [name|date|size] (default is the order of the registry)")
 
-		readTags(release, imagesSize, imagesPage)
-	},
+	return subcommand
 }
 
 func convertToHuman(bytes int) string {
+	if bytes == 0 {
+		// registries not exposing the size of the tags
+		return "-"
+	}
+
 	return fmt.Sprintf("%d MB", (bytes / 1000000))
 }
 
-func printTagsAsTable(data [][]string, currentPage int, totalPages int) {
+func printTagsAsTable(data [][]string, footer string) {
 	table := tablewriter.NewWriter(os.Stdout)
-	table.SetHeader([]string{"Image:Tag", "Size"})
-	table.SetColumnAlignment([]int{tablewriter.ALIGN_LEFT, tablewriter.ALIGN_RIGHT})
-	table.SetFooter([]string{"", fmt.Sprintf("%d of %d", currentPage, totalPages)}) // Add Footer
+	table.SetHeader([]string{"Image:Tag", "Size", "Last Updated", "Architectures"})
+	table.SetColumnAlignment([]int{
+		tablewriter.ALIGN_LEFT, tablewriter.ALIGN_RIGHT, tablewriter.ALIGN_LEFT, tablewriter.ALIGN_LEFT})
+	table.SetFooter([]string{"", "", "", footer}) // Add Footer
 
 	for _, v := range data {
 		table.Append(v)
@@ -160,65 +94,153 @@ func printTagsAsTable(data [][]string, currentPage int, totalPages int) {
 }
 
 func readTags(image liferay.Image, count int, page int) {
-	tagsPage := fmt.Sprintf("https://hub.docker.com/v2/repositories/%s/tags/?page_size=%d&page=%d", image.GetDockerHubTagsURL(), count, page)
+	tagSource := registry.GetCachedTagSource(image, tagsRefresh)
 
-	// Request the HTML page.
-	res, err := http.Get(tagsPage)
-	if err != nil {
-		log.WithFields(log.Fields{
-			"error": err,
-		}).Fatal("Error getting response from the server")
+	var tags []registry.Tag
+	var total int
+	var err error
+
+	// the filters and the sort apply to all the tags, not to a page of them
+	all := tagsAll || tagsFilter != "" || tagsSemver != "" || tagsSort != ""
+
+	if all {
+		tags, err = registry.GetAllTags(tagSource, count)
+		total = len(tags)
+	} else {
+		var tagsPage registry.TagsPage
+		tagsPage, err = tagSource.GetTags(page, count)
+		tags = tagsPage.Tags
+		total = tagsPage.Count
 	}
-	defer res.Body.Close()
 
-	if res.StatusCode == 404 {
+	if err == registry.ErrPageNotFound {
 		log.WithFields(log.Fields{
-			"statusCode": res.StatusCode,
+			"repository": tagSource.GetRepository(),
 		}).Warn("There are no available tags for that pagination. Please use --page and --size arguments to filter properly")
 		return
 	}
 
-	if res.StatusCode != 200 {
+	if err != nil {
 		log.WithFields(log.Fields{
-			"status":     res.Status,
-			"statusCode": res.StatusCode,
+			"repository": tagSource.GetRepository(),
+			"error":      err,
 		}).Fatal("Error getting response from the server")
 	}
 
-	// Load the JSON document
-	tagsResponse := new(tagsResponse)
-	err = json.NewDecoder(res.Body).Decode(tagsResponse)
+	filter := registry.TagsFilter{Regexp: tagsFilter, Semver: tagsSemver, Sort: tagsSort}
+
+	tags, err = filter.Apply(tags)
 	if err != nil {
 		log.WithFields(log.Fields{
-			"err": err,
-		}).Fatal("Error decoding response from the server")
+			"filter": tagsFilter,
+			"semver": tagsSemver,
+			"sort":   tagsSort,
+			"error":  err,
+		}).Fatal("Impossible to filter the tags")
 	}
 
 	data := [][]string{}
+	tagsOutput := []TagOutput{}
+
+	for _, t := range tags {
+		// For each item found, get the tag, its size, date and architectures
+		data = append(data, []string{
+			t.Name, convertToHuman(t.Size), t.LastUpdated, strings.Join(t.Architectures, ",")})
+		tagsOutput = append(tagsOutput, TagOutput{
+			Architectures: t.Architectures,
+			LastUpdated:   t.LastUpdated,
+			Name:          t.Name,
+			Size:          t.Size,
+		})
+	}
 
-	for _, t := range tagsResponse.Results {
-		// For each item found, get the tag and its size
-		tag := t.Name
-		size := t.Images[0].Size
-
-		data = append(data, []string{tag, convertToHuman(size)})
+	totalPages := 1
+	if !all {
+		totalPages = int(math.Ceil(float64(total) / float64(count)))
+	} else {
+		page = 1
 	}
 
-	if len(data) > 0 {
-		totalPages := int(math.Ceil(float64(tagsResponse.Count) / float64(count)))
-		if count > tagsResponse.Count {
-			count = tagsResponse.Count
+	if isStructuredOutput() {
+		outputCount := total
+		if all {
+			outputCount = len(tags)
 		}
 
-		log.WithFields(log.Fields{
-			"images":      tagsResponse.Count,
-			"elements":    count,
-			"currentPage": page,
-			"totalPages":  totalPages,
-		}).Infof("There are %d images, showing %d elements in page %d of %d", tagsResponse.Count, count, page, totalPages)
+		printStructuredOutput(TagsOutput{
+			Repository:  tagSource.GetRepository(),
+			Count:       outputCount,
+			CurrentPage: page,
+			TotalPages:  totalPages,
+			Tags:        tagsOutput,
+		})
+		return
+	}
 
-		printTagsAsTable(data, page, totalPages)
-	} else {
+	if len(data) == 0 {
 		log.Info("There are no available tags for that pagination. Please use --page and --size arguments to filter properly")
+		return
 	}
+
+	if all {
+		log.WithFields(log.Fields{
+			"images":   total,
+			"elements": len(data),
+		}).Infof("There are %d images, showing %d elements", total, len(data))
+
+		printTagsAsTable(data, fmt.Sprintf("%d of %d", len(data), total))
+		return
+	}
+
+	if count > total {
+		count = total
+	}
+
+	log.WithFields(log.Fields{
+		"images":      total,
+		"elements":    count,
+		"matching":    len(data),
+		"currentPage": page,
+		"totalPages":  totalPages,
+	}).Infof("There are %d images, showing %d elements in page %d of %d", total, count, page, totalPages)
+
+	printTagsAsTable(data, fmt.Sprintf("%d of %d", page, totalPages))
+}
+
+// resolveTag resolves the tag of the image if it is a symbolic selector, as "7.2.x", "latest-ga"
+// or "newest", using the listing of tags of the image. Concrete tags are returned as they are
+func resolveTag(image liferay.Image) string {
+	tag := image.GetTag()
+
+	if !registry.IsSymbolicTag(tag) {
+		return tag
+	}
+
+	tagSource := registry.GetCachedTagSource(image, false)
+
+	tags, err := registry.GetAllTags(tagSource, 100)
+	if err != nil {
+		log.WithFields(log.Fields{
+			"repository": tagSource.GetRepository(),
+			"tag":        tag,
+			"error":      err,
+		}).Fatal("Could not read the tags to resolve the symbolic tag")
+	}
+
+	resolved, err := registry.ResolveTag(tag, tags)
+	if err != nil {
+		log.WithFields(log.Fields{
+			"repository": tagSource.GetRepository(),
+			"tag":        tag,
+			"error":      err,
+		}).Fatal("Could not resolve the symbolic tag")
+	}
+
+	log.WithFields(log.Fields{
+		"repository": tagSource.GetRepository(),
+		"selector":   tag,
+		"tag":        resolved,
+	}).Infof("Symbolic tag %s resolved to %s", tag, resolved)
+
+	return resolved
 }
@@ -0,0 +1,212 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	docker "github.com/mdelapenya/lpn/docker"
+	liferay "github.com/mdelapenya/lpn/liferay"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+// autoUpgradeProperty portal property running the database upgrade on startup
+const autoUpgradeProperty = "upgrade.database.auto.run=true"
+
+var tagToUpgrade string
+var upgradeRollback bool
+var upgradeTimeout time.Duration
+
+func init() {
+	rootCmd.AddCommand(upgradeCmd)
+
+	addPortalSubcommands(upgradeCmd, newUpgradeCmd)
+}
+
+var upgradeCmd = &cobra.Command{
+	Use:   "upgrade",
+	Short: "Upgrades the Liferay Portal nook instance to a newer tag, keeping its database",
+	Long: `Upgrades the Liferay Portal nook instance to a newer tag, keeping its database.
+	The database is backed up, then the portal is stopped, and the new tag is started against the same
+	database container, running the database upgrade on startup. If the upgrade fails, the backup can be
+	restored, running the previous tag again.
+	For that, please run this command adding the image type as subcommand (see configuration file).`,
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		enableDebugLevel()
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		SubCommandInfo()
+	},
+	Annotations: map[string]string{containersAnnotation: containersRunning},
+}
+
+// newUpgradeCmd returns the subcommand upgrading the stack of a portal image type
+func newUpgradeCmd(image liferay.Image) *cobra.Command {
+	subcommand := &cobra.Command{
+		Short: "Upgrades the " + image.GetDescription() + " instance to a newer tag",
+		Long:  `Upgrades the ` + image.GetDescription() + ` instance, identified by [` + image.GetContainerName() + `], to a newer tag, keeping its database.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			upgradeStack(image.GetType())
+		},
+	}
+
+	subcommand.Flags().StringVar(&tagToUpgrade, "to", "", "Sets the image tag to upgrade to. Symbolic tags are resolved against the registry: [7.2.x|latest-ga|newest]")
+	subcommand.Flags().BoolVar(&upgradeRollback, "rollback", false, "Rolls back to the backup without asking if the upgrade fails")
+	subcommand.Flags().DurationVar(&upgradeTimeout, "timeout", 30*time.Minute, "Sets the maximum time to wait for the upgraded portal to start up")
+	subcommand.MarkFlagRequired("to")
+
+	return subcommand
+}
+
+// confirm asks a yes/no question in the standard input, returning false if it cannot be read
+func confirm(question string) bool {
+	fmt.Fprintf(os.Stderr, "%s [y/N]: ", question)
+
+	answer, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil {
+		return false
+	}
+
+	answer = strings.ToLower(strings.TrimSpace(answer))
+
+	return answer == "y" || answer == "yes"
+}
+
+// upgradeStack upgrades the running stack of a portal image type to the tag of the --to flag
+func upgradeStack(t string) {
+	currentImage := newImage(t, "")
+
+	settings, err := docker.GetRunSettings(currentImage)
+	if err != nil {
+		log.WithFields(log.Fields{
+			"container": currentImage.GetContainerName(),
+			"error":     err,
+		}).Fatal("Impossible to read the settings of the container. Please run it first")
+	}
+
+	if settings.Datastore == "hsql" {
+		log.WithFields(log.Fields{
+			"container": currentImage.GetContainerName(),
+		}).Fatal("The upgrade requires a database container. Please run the stack using the --datastore flag")
+	}
+
+	currentImage = newImage(t, settings.Tag)
+	targetImage := newImage(t, tagToUpgrade)
+	targetImage = newImage(t, resolveTag(targetImage))
+
+	if targetImage.GetTag() == currentImage.GetTag() {
+		log.WithFields(log.Fields{
+			"container": currentImage.GetContainerName(),
+			"tag":       currentImage.GetTag(),
+		}).Fatal("The stack is already running the tag to upgrade to. Please select a different one with the --to flag")
+	}
+
+	database := docker.GetDatabase(currentImage, settings.Datastore)
+
+	log.WithFields(log.Fields{
+		"container": currentImage.GetContainerName(),
+		"from":      currentImage.GetTag(),
+		"to":        targetImage.GetTag(),
+		"datastore": settings.Datastore,
+	}).Info("Upgrading the stack")
+
+	// the database is backed up from a cleanly stopped portal
+	err = docker.StopPortalContainer(currentImage, docker.DefaultStopTimeout)
+	if err != nil {
+		log.WithFields(log.Fields{
+			"container": currentImage.GetContainerName(),
+			"error":     err,
+		}).Fatal("Impossible to stop the portal")
+	}
+
+	backupPath, err := docker.BackupDatabase(database)
+	if err != nil {
+		log.WithFields(log.Fields{
+			"container": database.GetContainerName(),
+			"error":     err,
+		}).Fatal("Impossible to back up the database. The portal has been stopped, but not upgraded")
+	}
+
+	err = runUpgradedPortal(targetImage, database, settings)
+	if err == nil {
+		log.WithFields(log.Fields{
+			"container": targetImage.GetContainerName(),
+			"image":     targetImage.GetFullyQualifiedName(),
+			"backup":    backupPath,
+		}).Info("The stack has been upgraded successfully")
+		return
+	}
+
+	log.WithFields(log.Fields{
+		"container": targetImage.GetContainerName(),
+		"image":     targetImage.GetFullyQualifiedName(),
+		"backup":    backupPath,
+		"error":     err,
+	}).Error("The upgrade failed")
+
+	if !upgradeRollback && !confirm("Roll back to the backup and run "+currentImage.GetTag()+" again?") {
+		log.WithFields(log.Fields{
+			"backup": backupPath,
+		}).Fatal("The upgrade failed. The failed portal has been kept for inspection")
+	}
+
+	rollback(currentImage, database, settings, backupPath)
+
+	log.WithFields(log.Fields{
+		"container": currentImage.GetContainerName(),
+		"image":     currentImage.GetFullyQualifiedName(),
+	}).Fatal("The upgrade failed. The stack has been rolled back")
+}
+
+// runUpgradedPortal replaces the portal container with one of the target image, linked to the same
+// database, and follows its startup until the upgrade finishes
+func runUpgradedPortal(targetImage liferay.Image, database docker.DatabaseImage, settings docker.RunSettings) error {
+	err := docker.RemoveContainer(targetImage.GetContainerName())
+	if err != nil {
+		return err
+	}
+
+	options := settings.RunOptions
+	options.TransientProperties = []string{autoUpgradeProperty}
+
+	err = docker.RunLiferayDockerImage(targetImage, database, options)
+	if err != nil {
+		return err
+	}
+
+	return docker.FollowUpgrade(targetImage, upgradeTimeout)
+}
+
+// rollback restores the database backup and runs the previous image again
+func rollback(
+	image liferay.Image, database docker.DatabaseImage, settings docker.RunSettings, backupPath string) {
+
+	err := docker.RemoveContainer(image.GetContainerName())
+	if err != nil {
+		log.WithFields(log.Fields{
+			"container": image.GetContainerName(),
+			"error":     err,
+		}).Warn("Impossible to remove the failed portal")
+	}
+
+	err = docker.RestoreDatabase(database, backupPath)
+	if err != nil {
+		log.WithFields(log.Fields{
+			"container": database.GetContainerName(),
+			"backup":    backupPath,
+			"error":     err,
+		}).Fatal("Impossible to restore the database")
+	}
+
+	err = docker.RunLiferayDockerImage(image, database, settings.RunOptions)
+	if err != nil {
+		log.WithFields(log.Fields{
+			"container": image.GetContainerName(),
+			"error":     err,
+		}).Fatal("Impossible to run the previous portal")
+	}
+}
@@ -29,6 +29,16 @@ var versionCmd = &cobra.Command{
 
 		dockerClientVersion, dockerServerVersion, _ := docker.GetDockerVersion()
 
+		if isStructuredOutput() {
+			printStructuredOutput(VersionOutput{
+				Lpn:          string(version),
+				DockerClient: dockerClientVersion,
+				DockerServer: dockerServerVersion.Version,
+				Golang:       dockerServerVersion.GoVersion,
+			})
+			return
+		}
+
 		log.WithFields(log.Fields{
 			"lpn":          string(version),
 			"dockerClient": dockerClientVersion,
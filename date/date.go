@@ -1,6 +1,7 @@
 package date
 
 import (
+	"fmt"
 	"time"
 
 	"github.com/vjeantet/jodaTime"
@@ -8,3 +9,25 @@ import (
 
 // CurrentDate represents current date
 var CurrentDate = jodaTime.Format("YYYYMMdd", time.Now())
+
+// LastTags returns the tags representing the last days in the YYYYMMdd format, starting from
+// the given time, in the time zone of the given time
+func LastTags(from time.Time, days int) []string {
+	tags := []string{}
+
+	for i := 0; i < days; i++ {
+		tags = append(tags, jodaTime.Format("YYYYMMdd", from.AddDate(0, 0, -i)))
+	}
+
+	return tags
+}
+
+// ParseTag converts a date in the YYYY-MM-dd format into the tag representing it
+func ParseTag(date string) (string, error) {
+	t, err := time.ParseInLocation("2006-01-02", date, time.Local)
+	if err != nil {
+		return "", fmt.Errorf("%s is not a valid date. Please use the YYYY-MM-dd format: %v", date, err)
+	}
+
+	return jodaTime.Format("YYYYMMdd", t), nil
+}
@@ -18,3 +18,25 @@ func TestCurrentDate(t *testing.T) {
 
 	assert.Equal(year+month+day, CurrentDate, "Date not properly formed.")
 }
+
+func TestLastTags(t *testing.T) {
+	assert := assert.New(t)
+
+	from := time.Date(2019, time.March, 2, 8, 0, 0, 0, time.Local)
+
+	assert.Equal([]string{"20190302", "20190301", "20190228"}, LastTags(from, 3))
+}
+
+func TestParseTag(t *testing.T) {
+	assert := assert.New(t)
+
+	tag, err := ParseTag("2019-10-20")
+	assert.Nil(err)
+	assert.Equal("20191020", tag)
+}
+
+func TestParseTagInvalid(t *testing.T) {
+	_, err := ParseTag("20191020")
+
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,234 @@
+package docker
+
+import (
+	"archive/tar"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// buildTar writes a TAR stream of a local file or directory, recursively, naming its root entry
+// as rootName. The entries are owned by the uid and gid passed, unless they are negative, in which
+// case the local ownership is kept
+func buildTar(writer io.Writer, source string, rootName string, uid int, gid int) error {
+	tarWriter := tar.NewWriter(writer)
+
+	err := filepath.Walk(source, func(file string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		link := ""
+		if info.Mode()&os.ModeSymlink != 0 {
+			link, err = os.Readlink(file)
+			if err != nil {
+				return err
+			}
+		}
+
+		header, err := tar.FileInfoHeader(info, link)
+		if err != nil {
+			return err
+		}
+
+		relativePath, err := filepath.Rel(source, file)
+		if err != nil {
+			return err
+		}
+
+		header.Name = path.Join(rootName, filepath.ToSlash(relativePath))
+		if info.IsDir() {
+			header.Name += "/"
+		}
+
+		if uid >= 0 && gid >= 0 {
+			header.Uid = uid
+			header.Gid = gid
+			header.Uname = ""
+			header.Gname = ""
+		}
+
+		err = tarWriter.WriteHeader(header)
+		if err != nil {
+			return err
+		}
+
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+
+		content, err := os.Open(file)
+		if err != nil {
+			return err
+		}
+		defer content.Close()
+
+		_, err = io.Copy(tarWriter, content)
+
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	return tarWriter.Close()
+}
+
+// extractTar extracts the TAR stream copied from a container into a local directory, renaming its
+// root entry as rootName, if not empty. Modification times are kept, and ownership too when
+// running as root
+func extractTar(reader io.Reader, target string, rootName string) error {
+	target, err := filepath.Abs(target)
+	if err != nil {
+		return err
+	}
+
+	tarReader := tar.NewReader(reader)
+
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		name := header.Name
+		if rootName != "" {
+			parts := strings.SplitN(strings.TrimPrefix(name, "/"), "/", 2)
+			parts[0] = rootName
+			name = strings.Join(parts, "/")
+		}
+
+		file := filepath.Join(target, filepath.FromSlash(name))
+		if file != target && !strings.HasPrefix(file, target+string(os.PathSeparator)) {
+			return fmt.Errorf("The archive contains an entry outside of %s: %s", target, header.Name)
+		}
+
+		// a link of the archive could have been extracted in place of a parent directory
+		err = checkNoSymlinkParents(target, file)
+		if err != nil {
+			return err
+		}
+
+		switch header.Typeflag {
+		case tar.TypeDir, tar.TypeReg, tar.TypeRegA:
+		case tar.TypeSymlink:
+			if !isLinkInside(target, file, header.Linkname) {
+				log.WithFields(log.Fields{
+					"link":   header.Name,
+					"target": header.Linkname,
+				}).Warn("Skipping a link of the archive pointing outside of the destination")
+				continue
+			}
+		default:
+			continue
+		}
+
+		err = prepareDestination(file, header.Typeflag)
+		if err != nil {
+			return err
+		}
+
+		mode := os.FileMode(header.Mode).Perm()
+
+		switch header.Typeflag {
+		case tar.TypeDir:
+			err = os.MkdirAll(file, mode|0700)
+		case tar.TypeSymlink:
+			err = os.Symlink(header.Linkname, file)
+		default:
+			err = extractTarFile(tarReader, file, mode)
+			if err == nil {
+				err = os.Chtimes(file, header.ModTime, header.ModTime)
+			}
+		}
+
+		if err == nil && os.Geteuid() == 0 {
+			err = os.Lchown(file, header.Uid, header.Gid)
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func extractTarFile(reader io.Reader, file string, mode os.FileMode) error {
+	err := os.MkdirAll(filepath.Dir(file), 0755)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(file, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, mode)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = io.Copy(f, reader)
+
+	return err
+}
+
+// prepareDestination checks what is at the destination of an entry of the archive. Links are
+// removed, so that they are replaced instead of written through, and files and directories are
+// only replaced by entries of their type
+func prepareDestination(file string, typeflag byte) error {
+	info, err := os.Lstat(file)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case info.Mode()&os.ModeSymlink != 0:
+		return os.Remove(file)
+	case info.IsDir() && typeflag == tar.TypeDir:
+		return nil
+	case info.Mode().IsRegular() && (typeflag == tar.TypeReg || typeflag == tar.TypeRegA):
+		return nil
+	}
+
+	return fmt.Errorf("The archive would replace %s with an entry of a different type", file)
+}
+
+// checkNoSymlinkParents returns an error if any directory between the target and the file is a
+// symbolic link, so that nothing is written outside of the target through it
+func checkNoSymlinkParents(target string, file string) error {
+	for dir := filepath.Dir(file); dir != target && strings.HasPrefix(dir, target); dir = filepath.Dir(dir) {
+		info, err := os.Lstat(dir)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		if info.Mode()&os.ModeSymlink != 0 {
+			return fmt.Errorf("The archive writes %s through the link %s", file, dir)
+		}
+	}
+
+	return nil
+}
+
+// isLinkInside returns if a relative link resolves inside of the target. Absolute links are
+// outside of it, as they resolve against the local filesystem
+func isLinkInside(target string, file string, link string) bool {
+	if filepath.IsAbs(filepath.FromSlash(link)) || strings.HasPrefix(link, "/") {
+		return false
+	}
+
+	resolved := filepath.Join(filepath.Dir(file), filepath.FromSlash(link))
+
+	return resolved == target || strings.HasPrefix(resolved, target+string(os.PathSeparator))
+}
@@ -0,0 +1,338 @@
+package docker
+
+import (
+	"archive/tar"
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// tarEntry entry of a TAR stream built for the tests. Links have a link name, directories end
+// with a slash, and the rest of entries are files
+type tarEntry struct {
+	name     string
+	content  string
+	linkname string
+}
+
+func newTar(t *testing.T, entries ...tarEntry) *bytes.Buffer {
+	var buffer bytes.Buffer
+	tarWriter := tar.NewWriter(&buffer)
+
+	for _, entry := range entries {
+		header := &tar.Header{Name: entry.name, Mode: 0644, Size: int64(len(entry.content))}
+
+		switch {
+		case entry.linkname != "":
+			header.Typeflag = tar.TypeSymlink
+			header.Linkname = entry.linkname
+			header.Size = 0
+		case entry.name[len(entry.name)-1] == '/':
+			header.Typeflag = tar.TypeDir
+			header.Mode = 0755
+		default:
+			header.Typeflag = tar.TypeReg
+		}
+
+		err := tarWriter.WriteHeader(header)
+		if err == nil {
+			_, err = tarWriter.Write([]byte(entry.content))
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	err := tarWriter.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return &buffer
+}
+
+func newTempDir(t *testing.T) (string, func()) {
+	dir, err := ioutil.TempDir("", "lpn-archive")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return dir, func() { os.RemoveAll(dir) }
+}
+
+func TestExtractTar(t *testing.T) {
+	target, cleanUp := newTempDir(t)
+	defer cleanUp()
+
+	reader := newTar(t,
+		tarEntry{name: "logs/"},
+		tarEntry{name: "logs/liferay.log", content: "startup"},
+		tarEntry{name: "logs/latest.log", linkname: "liferay.log"})
+
+	err := extractTar(reader, target, "")
+
+	assert := assert.New(t)
+
+	assert.Nil(err)
+
+	content, err := ioutil.ReadFile(filepath.Join(target, "logs", "liferay.log"))
+	assert.Nil(err)
+	assert.Equal("startup", string(content))
+
+	link, err := os.Readlink(filepath.Join(target, "logs", "latest.log"))
+	assert.Nil(err)
+	assert.Equal("liferay.log", link)
+}
+
+func TestExtractTarRenamesRootEntry(t *testing.T) {
+	target, cleanUp := newTempDir(t)
+	defer cleanUp()
+
+	reader := newTar(t,
+		tarEntry{name: "logs/"},
+		tarEntry{name: "logs/liferay.log", content: "startup"})
+
+	err := extractTar(reader, target, "portal-logs")
+
+	assert := assert.New(t)
+
+	assert.Nil(err)
+
+	content, err := ioutil.ReadFile(filepath.Join(target, "portal-logs", "liferay.log"))
+	assert.Nil(err)
+	assert.Equal("startup", string(content))
+}
+
+func TestExtractTarRejectsEntriesOutsideTarget(t *testing.T) {
+	target, cleanUp := newTempDir(t)
+	defer cleanUp()
+
+	reader := newTar(t, tarEntry{name: "../outside.txt", content: "outside"})
+
+	err := extractTar(reader, filepath.Join(target, "copy"), "")
+
+	assert := assert.New(t)
+
+	assert.NotNil(err)
+
+	_, err = os.Stat(filepath.Join(target, "outside.txt"))
+	assert.True(os.IsNotExist(err))
+}
+
+func TestExtractTarSkipsAbsoluteLinks(t *testing.T) {
+	target, cleanUp := newTempDir(t)
+	defer cleanUp()
+
+	reader := newTar(t,
+		tarEntry{name: "data/"},
+		tarEntry{name: "data/passwd", linkname: "/etc/passwd"})
+
+	err := extractTar(reader, target, "")
+
+	assert := assert.New(t)
+
+	assert.Nil(err)
+
+	_, err = os.Lstat(filepath.Join(target, "data", "passwd"))
+	assert.True(os.IsNotExist(err))
+}
+
+func TestExtractTarSkipsLinksOutsideTarget(t *testing.T) {
+	target, cleanUp := newTempDir(t)
+	defer cleanUp()
+
+	reader := newTar(t,
+		tarEntry{name: "data/"},
+		tarEntry{name: "data/parent", linkname: "../.."})
+
+	err := extractTar(reader, filepath.Join(target, "copy"), "")
+
+	assert := assert.New(t)
+
+	assert.Nil(err)
+
+	_, err = os.Lstat(filepath.Join(target, "copy", "data", "parent"))
+	assert.True(os.IsNotExist(err))
+}
+
+func TestExtractTarRejectsWritingThroughLinks(t *testing.T) {
+	target, cleanUp := newTempDir(t)
+	defer cleanUp()
+
+	reader := newTar(t,
+		tarEntry{name: "data/"},
+		tarEntry{name: "data/modules/"},
+		tarEntry{name: "data/link", linkname: "modules"},
+		tarEntry{name: "data/link/file.txt", content: "through the link"})
+
+	err := extractTar(reader, target, "")
+
+	assert := assert.New(t)
+
+	assert.NotNil(err)
+
+	_, err = os.Stat(filepath.Join(target, "data", "modules", "file.txt"))
+	assert.True(os.IsNotExist(err))
+}
+
+func TestExtractTarReplacesLinksInsteadOfWritingThroughThem(t *testing.T) {
+	target, cleanUp := newTempDir(t)
+	defer cleanUp()
+
+	outside, cleanUpOutside := newTempDir(t)
+	defer cleanUpOutside()
+
+	outsideFile := filepath.Join(outside, "hosts")
+	err := ioutil.WriteFile(outsideFile, []byte("localhost"), 0644)
+	if err == nil {
+		err = os.Symlink(outsideFile, filepath.Join(target, "portal.log"))
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = extractTar(newTar(t, tarEntry{name: "portal.log", content: "startup"}), target, "")
+
+	assert := assert.New(t)
+
+	assert.Nil(err)
+
+	content, err := ioutil.ReadFile(outsideFile)
+	assert.Nil(err)
+	assert.Equal("localhost", string(content))
+
+	info, err := os.Lstat(filepath.Join(target, "portal.log"))
+	assert.Nil(err)
+	assert.True(info.Mode().IsRegular())
+}
+
+func TestExtractTarDoesNotReplaceDirectoriesWithFiles(t *testing.T) {
+	target, cleanUp := newTempDir(t)
+	defer cleanUp()
+
+	err := os.MkdirAll(filepath.Join(target, "deploy", "modules"), 0755)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = extractTar(newTar(t, tarEntry{name: "deploy", content: "file"}), target, "")
+
+	assert := assert.New(t)
+
+	assert.NotNil(err)
+
+	info, err := os.Stat(filepath.Join(target, "deploy", "modules"))
+	assert.Nil(err)
+	assert.True(info.IsDir())
+}
+
+func TestBuildTar(t *testing.T) {
+	source, cleanUp := newTempDir(t)
+	defer cleanUp()
+
+	err := os.MkdirAll(filepath.Join(source, "modules"), 0755)
+	if err == nil {
+		err = ioutil.WriteFile(filepath.Join(source, "modules", "module.jar"), []byte("jar"), 0644)
+	}
+	if err == nil {
+		err = os.Symlink("modules/module.jar", filepath.Join(source, "latest.jar"))
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buffer bytes.Buffer
+	err = buildTar(&buffer, source, "deploy", 1000, 1001)
+
+	assert := assert.New(t)
+
+	assert.Nil(err)
+
+	headers := map[string]*tar.Header{}
+	tarReader := tar.NewReader(&buffer)
+	for {
+		header, err := tarReader.Next()
+		if err != nil {
+			break
+		}
+
+		headers[header.Name] = header
+	}
+
+	assert.Len(headers, 4)
+	assert.Contains(headers, "deploy/")
+	assert.Contains(headers, "deploy/modules/")
+	assert.Contains(headers, "deploy/modules/module.jar")
+	assert.Contains(headers, "deploy/latest.jar")
+
+	assert.Equal("modules/module.jar", headers["deploy/latest.jar"].Linkname)
+
+	for _, header := range headers {
+		assert.Equal(1000, header.Uid)
+		assert.Equal(1001, header.Gid)
+		assert.Equal("", header.Uname)
+	}
+}
+
+func TestBuildTarFile(t *testing.T) {
+	source, cleanUp := newTempDir(t)
+	defer cleanUp()
+
+	file := filepath.Join(source, "activation-key.xml")
+	err := ioutil.WriteFile(file, []byte("<license/>"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buffer bytes.Buffer
+	err = buildTar(&buffer, file, "license.xml", -1, -1)
+
+	assert := assert.New(t)
+
+	assert.Nil(err)
+
+	tarReader := tar.NewReader(&buffer)
+	header, err := tarReader.Next()
+	assert.Nil(err)
+	assert.Equal("license.xml", header.Name)
+	assert.Equal(int64(len("<license/>")), header.Size)
+}
+
+func TestBuildTarAndExtractTar(t *testing.T) {
+	source, cleanUp := newTempDir(t)
+	defer cleanUp()
+
+	target, cleanUpTarget := newTempDir(t)
+	defer cleanUpTarget()
+
+	err := os.MkdirAll(filepath.Join(source, "logs"), 0755)
+	if err == nil {
+		err = ioutil.WriteFile(filepath.Join(source, "logs", "liferay.log"), []byte("startup"), 0644)
+	}
+	if err == nil {
+		err = os.Symlink("liferay.log", filepath.Join(source, "logs", "latest.log"))
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buffer bytes.Buffer
+	err = buildTar(&buffer, source, "copy", -1, -1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = extractTar(&buffer, target, "")
+
+	assert := assert.New(t)
+
+	assert.Nil(err)
+
+	content, err := ioutil.ReadFile(filepath.Join(target, "copy", "logs", "latest.log"))
+	assert.Nil(err)
+	assert.Equal("startup", string(content))
+}
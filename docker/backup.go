@@ -0,0 +1,124 @@
+package docker
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"time"
+
+	types "github.com/docker/docker/api/types"
+	internal "github.com/mdelapenya/lpn/internal"
+	log "github.com/sirupsen/logrus"
+)
+
+// BackupDatabase copies the data folder of the database container into a TAR file under the
+// backups directory of the workspace, returning its path. The database container is stopped
+// while copying, so that the backup is consistent, and started again afterwards
+func BackupDatabase(database DatabaseImage) (string, error) {
+	dockerClient := getDockerClient()
+
+	err := StopContainer(database.GetContainerName())
+	if err != nil {
+		return "", err
+	}
+	defer StartContainer(database.GetContainerName())
+
+	backupsDir := filepath.Join(internal.LpnWorkspace, "backups")
+	err = os.MkdirAll(backupsDir, 0755)
+	if err != nil {
+		return "", err
+	}
+
+	backupPath := filepath.Join(
+		backupsDir,
+		fmt.Sprintf("%s-%s.tar", database.GetContainerName(), time.Now().Format("20060102150405")))
+
+	reader, _, err := dockerClient.CopyFromContainer(
+		context.Background(), database.GetContainerName(), database.GetDataFolder())
+	if err != nil {
+		log.WithFields(log.Fields{
+			"container":  database.GetContainerName(),
+			"dataFolder": database.GetDataFolder(),
+			"error":      err,
+		}).Error("Could not copy the data folder from the container")
+		return "", err
+	}
+	defer reader.Close()
+
+	file, err := os.Create(backupPath)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	_, err = io.Copy(file, reader)
+	if err != nil {
+		os.Remove(backupPath)
+		return "", err
+	}
+
+	log.WithFields(log.Fields{
+		"container": database.GetContainerName(),
+		"backup":    backupPath,
+	}).Info("Database has been backed up")
+
+	return backupPath, nil
+}
+
+// RestoreDatabase replaces the data folder of the database container with the content of a
+// backup created by BackupDatabase. The current data is kept aside in the workspace
+func RestoreDatabase(database DatabaseImage, backupPath string) error {
+	dockerClient := getDockerClient()
+
+	backup, err := os.Open(backupPath)
+	if err != nil {
+		return err
+	}
+	defer backup.Close()
+
+	err = StopContainer(database.GetContainerName())
+	if err != nil {
+		return err
+	}
+
+	dataPath := filepath.Join(internal.LpnWorkspace, database.GetContainerName())
+	discardedPath := fmt.Sprintf("%s.discarded-%s", dataPath, time.Now().Format("20060102150405"))
+
+	err = os.Rename(dataPath, discardedPath)
+	if err != nil {
+		return err
+	}
+
+	err = os.MkdirAll(dataPath, os.ModePerm)
+	if err != nil {
+		return err
+	}
+
+	log.WithFields(log.Fields{
+		"container": database.GetContainerName(),
+		"discarded": discardedPath,
+	}).Debug("Current database data has been kept aside")
+
+	// the TAR contains the data folder itself, so it's extracted into its parent
+	err = dockerClient.CopyToContainer(
+		context.Background(), database.GetContainerName(), path.Dir(database.GetDataFolder()),
+		backup, types.CopyToContainerOptions{AllowOverwriteDirWithFile: true})
+	if err != nil {
+		log.WithFields(log.Fields{
+			"container": database.GetContainerName(),
+			"backup":    backupPath,
+			"error":     err,
+		}).Error("Could not copy the backup to the container")
+		return err
+	}
+
+	log.WithFields(log.Fields{
+		"container": database.GetContainerName(),
+		"backup":    backupPath,
+	}).Info("Database has been restored")
+
+	return StartContainer(database.GetContainerName())
+}
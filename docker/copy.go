@@ -0,0 +1,187 @@
+package docker
+
+import (
+	"archive/tar"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	types "github.com/docker/docker/api/types"
+	liferay "github.com/mdelapenya/lpn/liferay"
+	log "github.com/sirupsen/logrus"
+)
+
+// CopyFromContainer copies a file or directory of the container of the image, recursively, to a
+// local path. If the local path is an existing directory, it's copied into it
+func CopyFromContainer(image liferay.Image, containerPath string, localPath string) error {
+	dockerClient := getDockerClient()
+
+	targetDir := filepath.Dir(localPath)
+	rootName := filepath.Base(localPath)
+
+	if info, err := os.Stat(localPath); err == nil && info.IsDir() {
+		targetDir = localPath
+		rootName = path.Base(containerPath)
+	}
+
+	reader, _, err := dockerClient.CopyFromContainer(context.Background(), image.GetContainerName(), containerPath)
+	if err != nil {
+		log.WithFields(log.Fields{
+			"container": image.GetContainerName(),
+			"path":      containerPath,
+			"error":     err,
+		}).Error("Could not copy from the container")
+		return err
+	}
+	defer reader.Close()
+
+	err = extractTar(reader, targetDir, rootName)
+	if err != nil {
+		return err
+	}
+
+	log.WithFields(log.Fields{
+		"container": image.GetContainerName(),
+		"from":      containerPath,
+		"to":        filepath.Join(targetDir, rootName),
+	}).Debug("Copied from the container")
+
+	return nil
+}
+
+// CopyToContainer copies a local file or directory, recursively, to a path of the container of the
+// image. If the path is an existing directory, it's copied into it. The copied files are owned by
+// the user running the portal, as the rest of the files of Liferay Home
+func CopyToContainer(image liferay.Image, localPath string, containerPath string) error {
+	dockerClient := getDockerClient()
+
+	targetDir := path.Dir(containerPath)
+	rootName := path.Base(containerPath)
+
+	stat, err := dockerClient.ContainerStatPath(context.Background(), image.GetContainerName(), containerPath)
+	if err == nil && stat.Mode.IsDir() {
+		targetDir = containerPath
+		rootName = filepath.Base(localPath)
+	}
+
+	uid, gid, err := getUserIDs(image)
+	if err != nil {
+		log.WithFields(log.Fields{
+			"container": image.GetContainerName(),
+			"user":      image.GetUser(),
+			"error":     err,
+		}).Warn("Could not read the ids of the user of the container. Keeping the local ownership")
+
+		uid, gid = -1, -1
+	}
+
+	reader, writer := io.Pipe()
+
+	go func() {
+		writer.CloseWithError(buildTar(writer, localPath, rootName, uid, gid))
+	}()
+
+	err = dockerClient.CopyToContainer(
+		context.Background(), image.GetContainerName(), targetDir,
+		reader, types.CopyToContainerOptions{AllowOverwriteDirWithFile: true})
+	reader.Close()
+	if err != nil {
+		log.WithFields(log.Fields{
+			"container": image.GetContainerName(),
+			"path":      containerPath,
+			"error":     err,
+		}).Error("Could not copy to the container")
+		return err
+	}
+
+	log.WithFields(log.Fields{
+		"container": image.GetContainerName(),
+		"from":      localPath,
+		"to":        path.Join(targetDir, rootName),
+	}).Debug("Copied to the container")
+
+	return nil
+}
+
+// getUserIDs returns the uid and gid of the user running the portal in the running container of
+// the image
+func getUserIDs(image liferay.Image) (int, int, error) {
+	ids := []int{}
+
+	for _, flag := range []string{"-u", "-g"} {
+		output, err := execCommandOutput(image.GetContainerName(), "", []string{"id", flag, image.GetUser()})
+		if err != nil {
+			return 0, 0, err
+		}
+
+		id, err := strconv.Atoi(strings.TrimSpace(output))
+		if err != nil {
+			return 0, 0, err
+		}
+
+		ids = append(ids, id)
+	}
+
+	return ids[0], ids[1], nil
+}
+
+// getCreatedContainerUserIDs returns the uid and gid of a user of a container which could not be
+// running, reading them from its /etc/passwd file. Numeric users are looked up by their uid
+func getCreatedContainerUserIDs(containerID string, user string) (int, int, error) {
+	content, err := readContainerFile(containerID, "/etc/passwd")
+	if err != nil {
+		return 0, 0, err
+	}
+
+	for _, line := range strings.Split(string(content), "\n") {
+		// name:password:uid:gid:comment:home:shell
+		fields := strings.Split(line, ":")
+		if len(fields) < 4 || (fields[0] != user && fields[2] != user) {
+			continue
+		}
+
+		uid, err := strconv.Atoi(fields[2])
+		if err != nil {
+			return 0, 0, err
+		}
+
+		gid, err := strconv.Atoi(fields[3])
+		if err != nil {
+			return 0, 0, err
+		}
+
+		return uid, gid, nil
+	}
+
+	return 0, 0, fmt.Errorf("The user %s does not exist in the container", user)
+}
+
+// readContainerFile returns the content of a file of a container, which could not be running
+func readContainerFile(containerName string, containerPath string) ([]byte, error) {
+	dockerClient := getDockerClient()
+
+	reader, _, err := dockerClient.CopyFromContainer(context.Background(), containerName, containerPath)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	tarReader := tar.NewReader(reader)
+
+	header, err := tarReader.Next()
+	if err != nil {
+		return nil, err
+	}
+
+	if header.Typeflag != tar.TypeReg && header.Typeflag != tar.TypeRegA {
+		return nil, fmt.Errorf("%s is not a file", containerPath)
+	}
+
+	return ioutil.ReadAll(tarReader)
+}
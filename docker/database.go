@@ -30,6 +30,11 @@ func GetAlias() string {
 	return "db"
 }
 
+// GetDatastores returns the datastores a portal can be run with, hsql being the embedded one
+func GetDatastores() []string {
+	return []string{"hsql", "mysql", "postgresql"}
+}
+
 // GetDatabase returns the proper database model
 func GetDatabase(image liferay.Image, datastore string) DatabaseImage {
 	if datastore == "mysql" {
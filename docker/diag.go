@@ -0,0 +1,118 @@
+package docker
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"time"
+
+	internal "github.com/mdelapenya/lpn/internal"
+	liferay "github.com/mdelapenya/lpn/liferay"
+	log "github.com/sirupsen/logrus"
+)
+
+// diagContainerDir directory of the container where the dumps are written before copying them
+const diagContainerDir = "/tmp"
+
+// GetDiagDir returns the default directory the dumps are copied to
+func GetDiagDir() string {
+	return filepath.Join(internal.LpnWorkspace, "diag")
+}
+
+// HeapDump dumps the heap of the portal running in the container of the image, copying it to a
+// timestamped directory under a local directory. It returns the path of the timestamped directory
+func HeapDump(image liferay.Image, localDir string) (string, error) {
+	return captureDiag(image, "heapdump", localDir, func(pid string, dir string) error {
+		_, err := execCommandOutput(image.GetContainerName(), image.GetUser(), []string{
+			"jmap", "-dump:live,format=b,file=" + path.Join(dir, "heapdump.hprof"), pid})
+
+		return err
+	})
+}
+
+// ThreadDump dumps the threads of the portal running in the container of the image a number of
+// times, waiting an interval between them, and copies the dumps to a timestamped directory under
+// a local directory. It returns the path of the timestamped directory
+func ThreadDump(image liferay.Image, count int, interval time.Duration, localDir string) (string, error) {
+	return captureDiag(image, "threaddump", localDir, func(pid string, dir string) error {
+		for i := 1; i <= count; i++ {
+			if i > 1 {
+				time.Sleep(interval)
+			}
+
+			dump := path.Join(dir, fmt.Sprintf("threaddump-%d-%s.txt", i, time.Now().Format("150405")))
+
+			_, err := execCommandOutput(image.GetContainerName(), image.GetUser(), []string{
+				"sh", "-c", fmt.Sprintf("jstack -l %s > %s", pid, dump)})
+			if err != nil {
+				return err
+			}
+
+			log.WithFields(log.Fields{
+				"container": image.GetContainerName(),
+				"dump":      dump,
+			}).Infof("Thread dump %d of %d captured", i, count)
+		}
+
+		return nil
+	})
+}
+
+// captureDiag runs the capture of a diagnostic against the Java process of the portal, as the user
+// running it, into a temporary directory of the container, which is copied to the local directory
+// and removed afterwards
+func captureDiag(
+	image liferay.Image, kind string, localDir string, capture func(pid string, dir string) error) (string, error) {
+
+	output, err := execCommandOutput(image.GetContainerName(), image.GetUser(), []string{"jcmd", "-l"})
+	if err != nil {
+		return "", err
+	}
+
+	pid, err := liferay.ParseJavaPID(output)
+	if err != nil {
+		return "", err
+	}
+
+	log.WithFields(log.Fields{
+		"container": image.GetContainerName(),
+		"pid":       pid,
+	}).Debug("Java process of the portal found")
+
+	name := fmt.Sprintf("%s-%s-%s", image.GetContainerName(), kind, time.Now().Format("20060102150405"))
+	containerDir := path.Join(diagContainerDir, name)
+
+	_, err = execCommandOutput(image.GetContainerName(), image.GetUser(), []string{"mkdir", "-p", containerDir})
+	if err != nil {
+		return "", err
+	}
+	defer execCommandOutput(image.GetContainerName(), image.GetUser(), []string{"rm", "-rf", containerDir})
+
+	err = capture(pid, containerDir)
+	if err != nil {
+		return "", err
+	}
+
+	err = os.MkdirAll(localDir, 0755)
+	if err != nil {
+		return "", err
+	}
+
+	dockerClient := getDockerClient()
+
+	reader, _, err := dockerClient.CopyFromContainer(context.Background(), image.GetContainerName(), containerDir)
+	if err != nil {
+		return "", err
+	}
+	defer reader.Close()
+
+	// the TAR contains the directory itself, so it's extracted into the local directory
+	err = extractTar(reader, localDir, "")
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(localDir, name), nil
+}
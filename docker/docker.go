@@ -11,13 +11,17 @@ import (
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"sort"
+	"strconv"
 	"strings"
+	"time"
 
 	types "github.com/docker/docker/api/types"
 	container "github.com/docker/docker/api/types/container"
 	filters "github.com/docker/docker/api/types/filters"
 	mount "github.com/docker/docker/api/types/mount"
 	client "github.com/docker/docker/client"
+	stdcopy "github.com/docker/docker/pkg/stdcopy"
 	nat "github.com/docker/go-connections/nat"
 	internal "github.com/mdelapenya/lpn/internal"
 	liferay "github.com/mdelapenya/lpn/liferay"
@@ -103,6 +107,18 @@ func CheckDockerContainerExists(containerName string) bool {
 	return false
 }
 
+// IsContainerRunning checks if the container exists and is running
+func IsContainerRunning(containerName string) bool {
+	dockerClient := getDockerClient()
+
+	containerJSON, err := dockerClient.ContainerInspect(context.Background(), containerName)
+	if err != nil || containerJSON.State == nil {
+		return false
+	}
+
+	return containerJSON.State.Running
+}
+
 // CheckDockerImageExists checks if the image is already present
 func CheckDockerImageExists(dockerImage string) bool {
 	dockerClient := getDockerClient()
@@ -238,6 +254,32 @@ func getDockerClient() *client.Client {
 	return instance
 }
 
+// GetContainerInstance returns the simple model of the container with that name
+func GetContainerInstance(containerName string) (ContainerInstance, error) {
+	dockerClient := getDockerClient()
+
+	containers, err := dockerClient.ContainerList(
+		context.Background(), types.ContainerListOptions{All: true})
+	if err != nil {
+		log.WithFields(log.Fields{
+			"error": err,
+		}).Error("Could not list all containers")
+		return ContainerInstance{}, err
+	}
+
+	for _, container := range containers {
+		if "/"+containerName == container.Names[0] {
+			return ContainerInstance{
+				ID:     container.ID,
+				Name:   containerName,
+				Status: container.Status,
+			}, nil
+		}
+	}
+
+	return ContainerInstance{}, errors.New("Error response from daemon: No such container: " + containerName)
+}
+
 // GetDockerImageFromRunningContainer gets the image name of the container
 func GetDockerImageFromRunningContainer(image liferay.Image) (string, error) {
 	dockerClient := getDockerClient()
@@ -272,6 +314,22 @@ func GetDockerImageFromRunningContainer(image liferay.Image) (string, error) {
 	return "", err
 }
 
+// GetDatabaseContainerName returns the name of the database container of the stack of the image
+func GetDatabaseContainerName(image liferay.Image) (string, error) {
+	containers, err := PsFilterByLabel("lpn-type=" + image.GetType())
+	if err != nil {
+		return "", err
+	}
+
+	for _, container := range containers {
+		if _, ok := container.Labels["db-type"]; ok && len(container.Names) > 0 {
+			return strings.TrimPrefix(container.Names[0], "/"), nil
+		}
+	}
+
+	return "", fmt.Errorf("There is no database container in the stack of %s", image.GetContainerName())
+}
+
 // GetDockerVersion returns the output of Docker version
 func GetDockerVersion() (string, types.Version, error) {
 	dockerClient := getDockerClient()
@@ -281,6 +339,88 @@ func GetDockerVersion() (string, types.Version, error) {
 	return dockerClient.ClientVersion(), serverVersion, err
 }
 
+// GetRunSettings reads the settings the portal container was run with from its inspect data
+func GetRunSettings(image liferay.Image) (RunSettings, error) {
+	dockerClient := getDockerClient()
+
+	containerJSON, err := dockerClient.ContainerInspect(context.Background(), image.GetContainerName())
+	if err != nil {
+		return RunSettings{}, err
+	}
+
+	settings := RunSettings{
+		Datastore: "hsql",
+		Image:     containerJSON.Config.Image,
+		Tag:       containerJSON.Config.Labels["lpn-tag"],
+	}
+
+	if settings.Tag == "" {
+		settings.Tag = settings.Image[strings.LastIndex(settings.Image, ":")+1:]
+	}
+
+	portBindings := containerJSON.HostConfig.PortBindings
+	settings.HTTPPort = getHostPort(portBindings, "8080/tcp")
+	settings.GogoShellPort = getHostPort(portBindings, "11311/tcp")
+	settings.DebugPort = getHostPort(portBindings, "9000/tcp")
+	settings.EnableDebug = settings.DebugPort != 0
+	settings.License = containerJSON.Config.Labels[LabelLicense]
+	settings.CPUs = float64(containerJSON.HostConfig.NanoCPUs) / 1e9
+	settings.MemLimit = containerJSON.HostConfig.Memory
+
+	if properties := containerJSON.Config.Labels[labelProperties]; properties != "" {
+		settings.Properties = strings.Split(properties, "\n")
+	}
+
+	if env := containerJSON.Config.Labels[labelEnv]; env != "" {
+		settings.Env = strings.Split(env, "\n")
+	}
+
+	for _, m := range containerJSON.HostConfig.Mounts {
+		if m.Type == mount.TypeBind {
+			settings.Mounts = append(settings.Mounts, liferay.Mount{
+				ReadOnly: m.ReadOnly,
+				Source:   m.Source,
+				Target:   m.Target,
+			})
+		}
+	}
+
+	for _, env := range containerJSON.Config.Env {
+		if strings.HasPrefix(env, jvmOptionsEnvVar+"=") {
+			settings.Memory = strings.TrimPrefix(env, jvmOptionsEnvVar+"=")
+		}
+	}
+
+	containers, err := PsFilterByLabel("lpn-type=" + image.GetType())
+	if err != nil {
+		return RunSettings{}, err
+	}
+
+	for _, container := range containers {
+		if dbType, ok := container.Labels["db-type"]; ok {
+			settings.Datastore = dbType
+
+			dbJSON, err := dockerClient.ContainerInspect(context.Background(), container.ID)
+			if err == nil {
+				settings.DbMemLimit = dbJSON.HostConfig.Memory
+			}
+		}
+	}
+
+	return settings, nil
+}
+
+func getHostPort(portBindings nat.PortMap, port nat.Port) int {
+	bindings := portBindings[port]
+	if len(bindings) == 0 {
+		return 0
+	}
+
+	hostPort, _ := strconv.Atoi(bindings[0].HostPort)
+
+	return hostPort
+}
+
 // inspect inspects a container
 func inspect(containerName string) types.ContainerJSON {
 	dockerClient := getDockerClient()
@@ -309,43 +449,194 @@ func GetTomcatPort(image liferay.Image) string {
 	return tomcatPortBinding[0].HostPort
 }
 
-// LogContainer show logs of a container in tail mode
-func LogContainer(image liferay.Image) {
+// LogOptions options the logs of a container are displayed with
+type LogOptions struct {
+	Follow     bool
+	Since      string
+	Tail       string
+	Timestamps bool
+}
+
+// LogContainer writes the logs of a container, demultiplexing its standard output and standard
+// error into two writers
+func LogContainer(containerName string, options LogOptions, stdout io.Writer, stderr io.Writer) error {
 	dockerClient := getDockerClient()
 
+	containerJSON, err := dockerClient.ContainerInspect(context.Background(), containerName)
+	if err != nil {
+		return err
+	}
+
 	reader, err := dockerClient.ContainerLogs(
-		context.Background(), image.GetContainerName(),
-		types.ContainerLogsOptions{ShowStdout: true, ShowStderr: true, Follow: true})
+		context.Background(), containerName,
+		types.ContainerLogsOptions{
+			Follow:     options.Follow,
+			ShowStderr: true,
+			ShowStdout: true,
+			Since:      options.Since,
+			Tail:       options.Tail,
+			Timestamps: options.Timestamps,
+		})
 	if err != nil {
-		log.WithFields(log.Fields{
-			"container": image.GetContainerName(),
-			"error":     err,
-		}).Fatal("Could not get container logs")
+		return err
+	}
+	defer reader.Close()
+
+	// the streams of containers with a TTY are not multiplexed
+	if containerJSON.Config != nil && containerJSON.Config.Tty {
+		_, err = io.Copy(stdout, reader)
+	} else {
+		_, err = stdcopy.StdCopy(stdout, stderr, reader)
 	}
 
-	_, err = io.Copy(os.Stdout, reader)
 	if err != nil && err != io.EOF {
-		log.WithFields(log.Fields{
-			"container": image.GetContainerName(),
-			"error":     err,
-		}).Fatal("Error following container logs")
+		return err
 	}
+
+	return nil
 }
 
-// PsFilterByLabel Retrieves all containers with a label
+// GetContainerTypes returns the image types of the portal containers created by lpn, sorted, only
+// the ones of the running containers if running is true
+func GetContainerTypes(running bool) ([]string, error) {
+	containers, err := PsFilterByLabel("lpn-type")
+	if err != nil {
+		return nil, err
+	}
+
+	containerTypes := []string{}
+	for _, container := range containers {
+		if _, ok := container.Labels["db-type"]; ok {
+			continue
+		}
+
+		if running && container.State != "running" {
+			continue
+		}
+
+		containerTypes = append(containerTypes, container.Labels["lpn-type"])
+	}
+
+	sort.Strings(containerTypes)
+
+	return containerTypes, nil
+}
+
+// GetLocalTags returns the tags of the images of the repository of the image present in the local
+// Docker installation
+func GetLocalTags(image liferay.Image) ([]string, error) {
+	dockerClient := getDockerClient()
+
+	images, err := dockerClient.ImageList(context.Background(), types.ImageListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	prefix := strings.TrimPrefix(image.GetRepository(), "docker.io/") + ":"
+
+	tags := []string{}
+	for _, summary := range images {
+		for _, repoTag := range summary.RepoTags {
+			if strings.HasPrefix(repoTag, prefix) {
+				tags = append(tags, strings.TrimPrefix(repoTag, prefix))
+			}
+		}
+	}
+
+	return tags, nil
+}
+
+// PsFilterByLabel Retrieves all containers with a label, belonging to the current profile. The
+// containers without profile label, created before the profiles, belong to the default one
 func PsFilterByLabel(label string) ([]types.Container, error) {
 	dockerClient := getDockerClient()
 
 	filters := filters.NewArgs()
 	filters.Add("label", label)
 
-	return dockerClient.ContainerList(
+	containers, err := dockerClient.ContainerList(
 		context.Background(), types.ContainerListOptions{
 			Size:    true,
 			All:     true,
 			Since:   "container",
 			Filters: filters,
 		})
+	if err != nil {
+		return nil, err
+	}
+
+	return filterProfileContainers(containers, internal.LpnProfile), nil
+}
+
+// filterProfileContainers returns the containers run with a profile, read from their profile label.
+// The containers without it were run before the profiles existed, so they belong to the default one
+func filterProfileContainers(containers []types.Container, profile string) []types.Container {
+	if profile == "" {
+		profile = internal.DefaultProfile
+	}
+
+	profileContainers := []types.Container{}
+	for _, container := range containers {
+		containerProfile := container.Labels[labelProfile]
+		if containerProfile == "" {
+			containerProfile = internal.DefaultProfile
+		}
+
+		if containerProfile == profile {
+			profileContainers = append(profileContainers, container)
+		}
+	}
+
+	return profileContainers
+}
+
+// InspectImageSettings detects the Liferay home, deploy folder and user of a local portal image
+// from its configuration, caching them per image digest
+func InspectImageSettings(image liferay.Image) error {
+	dockerClient := getDockerClient()
+
+	imageInspect, _, err := dockerClient.ImageInspectWithRaw(
+		context.Background(), strings.ReplaceAll(image.GetFullyQualifiedName(), "docker.io/", ""))
+	if err != nil {
+		return err
+	}
+
+	settings, ok := liferay.GetCachedImageSettings(imageInspect.ID)
+	if !ok && imageInspect.Config != nil {
+		settings = liferay.DetectImageSettings(
+			imageInspect.Config.Env, imageInspect.Config.WorkingDir, imageInspect.Config.User,
+			imageInspect.Config.Labels)
+
+		log.WithFields(log.Fields{
+			"image":        image.GetFullyQualifiedName(),
+			"digest":       imageInspect.ID,
+			"liferayHome":  settings.LiferayHome,
+			"deployFolder": settings.DeployFolder,
+			"user":         settings.User,
+		}).Debug("Image settings detected")
+	}
+
+	return liferay.SaveImageSettings(image.GetFullyQualifiedName(), imageInspect.ID, settings)
+}
+
+// getImageEnvVariable returns the value of an environment variable of the local image, or empty if
+// the image does not define it
+func getImageEnvVariable(image liferay.Image, name string) string {
+	dockerClient := getDockerClient()
+
+	imageInspect, _, err := dockerClient.ImageInspectWithRaw(
+		context.Background(), strings.ReplaceAll(image.GetFullyQualifiedName(), "docker.io/", ""))
+	if err != nil || imageInspect.Config == nil {
+		return ""
+	}
+
+	for _, env := range imageInspect.Config.Env {
+		if strings.HasPrefix(env, name+"=") {
+			return strings.TrimPrefix(env, name+"=")
+		}
+	}
+
+	return ""
 }
 
 // PullDockerImage downloads the image
@@ -448,8 +739,10 @@ func RemoveDockerImage(dockerImageName string) error {
 	return nil
 }
 
-// RunDatabaseDockerImage runs the image, setting the HTTP port and a volume for the data folder
-func RunDatabaseDockerImage(image DatabaseImage) error {
+// RunDatabaseDockerImage runs the image, setting the HTTP port, a volume for the data folder, the
+// memory limit of the container, if greater than zero, and the environment variables passed, which
+// override the ones of the database
+func RunDatabaseDockerImage(image DatabaseImage, memLimit int64, env []string) error {
 	if CheckDockerContainerExists(image.GetContainerName()) {
 		log.WithFields(log.Fields{
 			"container": image.GetContainerName(),
@@ -466,6 +759,8 @@ func RunDatabaseDockerImage(image DatabaseImage) error {
 	environmentVariables = append(environmentVariables, image.GetEnvVariables().Password)
 	environmentVariables = append(environmentVariables, image.GetEnvVariables().User)
 
+	environmentVariables = liferay.MergeEnvVariables(environmentVariables, env)
+
 	exposedPorts := map[nat.Port]struct{}{
 		natPort: {},
 	}
@@ -499,13 +794,17 @@ func RunDatabaseDockerImage(image DatabaseImage) error {
 			Env:          environmentVariables,
 			ExposedPorts: exposedPorts,
 			Labels: map[string]string{
-				"db-type":  image.GetType(),
-				"lpn-type": image.GetLpnType(),
+				"db-type":    image.GetType(),
+				"lpn-type":   image.GetLpnType(),
+				labelProfile: internal.LpnProfile,
 			},
 		},
 		&container.HostConfig{
 			PortBindings: portBindings,
 			Mounts:       mounts,
+			Resources: container.Resources{
+				Memory: memLimit,
+			},
 		},
 		nil, image.GetContainerName())
 	if err != nil {
@@ -536,11 +835,9 @@ func RunDatabaseDockerImage(image DatabaseImage) error {
 	return err
 }
 
-// RunLiferayDockerImage runs the image, setting the HTTP and GoGoShell ports for bundle, debug mode, and
-// jvmMemory if needed
-func RunLiferayDockerImage(
-	image liferay.Image, database DatabaseImage, httpPort int, gogoShellPort int, enableDebug bool,
-	debugPort int, memory string) error {
+// RunLiferayDockerImage runs the image, setting the HTTP and GoGoShell ports for bundle, debug mode,
+// jvmMemory and the activation key if needed
+func RunLiferayDockerImage(image liferay.Image, database DatabaseImage, options RunOptions) error {
 
 	if CheckDockerContainerExists(image.GetContainerName()) {
 		log.WithFields(log.Fields{
@@ -550,9 +847,40 @@ func RunLiferayDockerImage(
 		_ = RemoveDockerContainer(image)
 	}
 
-	port := fmt.Sprintf("%d", httpPort)
-	gogoPort := fmt.Sprintf("%d", gogoShellPort)
-	debuggerPort := fmt.Sprintf("%d", debugPort)
+	PullDockerImage(image.GetFullyQualifiedName())
+
+	err := InspectImageSettings(image)
+	if err != nil {
+		log.WithFields(log.Fields{
+			"image": image.GetFullyQualifiedName(),
+			"error": err,
+		}).Warn("Could not detect the settings of the image. Using the configured ones")
+	}
+
+	if database != nil {
+		RunDatabaseDockerImage(database, options.DbMemLimit, options.DbEnv)
+	}
+
+	return runPortalContainer(image, database, options)
+}
+
+// RecreatePortalContainer replaces the portal container of the stack with a new one of the same
+// local image, run with other options. The rest of the stack, as the database, is kept
+func RecreatePortalContainer(image liferay.Image, database DatabaseImage, options RunOptions) error {
+	err := RemoveContainer(image.GetContainerName())
+	if err != nil {
+		return err
+	}
+
+	return runPortalContainer(image, database, options)
+}
+
+// runPortalContainer creates and starts the portal container of the image, linked to the database
+// container if present
+func runPortalContainer(image liferay.Image, database DatabaseImage, options RunOptions) error {
+	port := fmt.Sprintf("%d", options.HTTPPort)
+	gogoPort := fmt.Sprintf("%d", options.GogoShellPort)
+	debuggerPort := fmt.Sprintf("%d", options.DebugPort)
 
 	environmentVariables := []string{}
 
@@ -566,31 +894,56 @@ func RunLiferayDockerImage(
 	portBindings["8080/tcp"] = buildPortBinding(port, "0.0.0.0")
 	portBindings["11311/tcp"] = buildPortBinding(gogoPort, "0.0.0.0")
 
-	if enableDebug {
+	if options.EnableDebug {
 		var port9000 struct{}
 		exposedPorts["9000/tcp"] = port9000
 
 		portBindings["9000/tcp"] = buildPortBinding(debuggerPort, "0.0.0.0")
 
-		debugEnvVarName := ""
+		environmentVariables = append(environmentVariables, image.GetDebugEnvVar()+"=true")
+	}
 
-		switch imageType := image.(type) {
-		case liferay.CE, liferay.Commerce, liferay.DXP, liferay.Nightly:
-			debugEnvVarName = "LIFERAY_JPDA_ENABLED"
-		case liferay.Release:
-			debugEnvVarName = "DEBUG_MODE"
-		default:
-			log.Fatalln("Non supported type", imageType)
+	jvmOptions := options.Memory
+	if options.JVMOptions != "" {
+		if jvmOptions == "" {
+			jvmOptions = getImageEnvVariable(image, jvmOptionsEnvVar)
 		}
 
-		environmentVariables = append(environmentVariables, debugEnvVarName+"=true")
+		jvmOptions = liferay.AppendJVMOptions(jvmOptions, options.JVMOptions)
 	}
 
-	if memory != "" {
-		environmentVariables = append(environmentVariables, "LIFERAY_JVM_OPTS="+memory)
+	if jvmOptions != "" {
+		environmentVariables = append(environmentVariables, jvmOptionsEnvVar+"="+jvmOptions)
 	}
 
-	PullDockerImage(image.GetFullyQualifiedName())
+	// the transient properties are not stored in the labels, so they are not run with again
+	for _, property := range liferay.MergeProperties(options.Properties, options.TransientProperties) {
+		envVariable, err := liferay.GetPropertyEnvVariable(property)
+		if err != nil {
+			return err
+		}
+
+		environmentVariables = append(environmentVariables, envVariable)
+	}
+
+	mounts := []mount.Mount{}
+
+	for _, m := range options.Mounts {
+		mounts = append(mounts, mount.Mount{
+			Type:     mount.TypeBind,
+			Source:   m.Source,
+			Target:   liferay.GetContainerPath(image, m.Target),
+			ReadOnly: m.ReadOnly,
+		})
+	}
+
+	if options.HotDeploy != "" {
+		mounts = append(mounts, mount.Mount{
+			Type:   mount.TypeBind,
+			Source: options.HotDeploy,
+			Target: image.GetDeployFolder(),
+		})
+	}
 
 	dockerClient := getDockerClient()
 
@@ -600,8 +953,6 @@ func RunLiferayDockerImage(
 		link := database.GetContainerName() + ":" + "db"
 		links = append(links, link)
 
-		RunDatabaseDockerImage(database)
-
 		environmentVariables = append(environmentVariables, "LIFERAY_JDBC_PERIOD_DEFAULT_PERIOD_DRIVER_UPPERCASEC_LASS_UPPERCASEN_AME="+database.GetJDBCConnection().DriverClassName)
 		environmentVariables = append(environmentVariables, "LIFERAY_JDBC_PERIOD_DEFAULT_PERIOD_PASSWORD="+database.GetJDBCConnection().Password)
 		environmentVariables = append(environmentVariables, "LIFERAY_JDBC_PERIOD_DEFAULT_PERIOD_URL="+database.GetJDBCConnection().URL)
@@ -612,20 +963,43 @@ func RunLiferayDockerImage(
 		environmentVariables = append(environmentVariables, "LIFERAY_RETRY_PERIOD_JDBC_PERIOD_ON_PERIOD_STARTUP_PERIOD_MAX_PERIOD_RETRIES=5")
 	}
 
+	// the variables of the user are merged last, so they override the ones set by lpn
+	environmentVariables = liferay.MergeEnvVariables(environmentVariables, options.Env)
+
+	labels := map[string]string{
+		"lpn-tag":    image.GetTag(),
+		"lpn-type":   image.GetType(),
+		labelProfile: internal.LpnProfile,
+	}
+
+	if len(options.Properties) > 0 {
+		labels[labelProperties] = strings.Join(options.Properties, "\n")
+	}
+
+	if len(options.Env) > 0 {
+		labels[labelEnv] = strings.Join(options.Env, "\n")
+	}
+
+	if options.License != "" {
+		labels[LabelLicense] = options.License
+	}
+
 	containerCreationResponse, err := dockerClient.ContainerCreate(
 		context.Background(),
 		&container.Config{
 			Image:        image.GetFullyQualifiedName(),
 			Env:          environmentVariables,
 			ExposedPorts: exposedPorts,
-			Labels: map[string]string{
-				"lpn-type": image.GetType(),
-			},
+			Labels:       labels,
 		},
 		&container.HostConfig{
 			Links:        links,
 			PortBindings: portBindings,
-			Mounts:       []mount.Mount{},
+			Mounts:       mounts,
+			Resources: container.Resources{
+				Memory:   options.MemLimit,
+				NanoCPUs: int64(options.CPUs * 1e9),
+			},
 		},
 		nil, image.GetContainerName())
 	if err != nil {
@@ -635,10 +1009,18 @@ func RunLiferayDockerImage(
 			"env":          environmentVariables,
 			"ports":        exposedPorts,
 			"portBindings": portBindings,
+			"mounts":       mounts,
 			"error":        err,
 		}).Fatal("Could not create container")
 	}
 
+	if options.License != "" {
+		err = installLicense(image, containerCreationResponse.ID, options.License)
+		if err != nil {
+			return err
+		}
+	}
+
 	err = dockerClient.ContainerStart(
 		context.Background(), containerCreationResponse.ID, types.ContainerStartOptions{})
 	if err == nil {
@@ -648,12 +1030,60 @@ func RunLiferayDockerImage(
 			"env":          environmentVariables,
 			"ports":        exposedPorts,
 			"portBindings": portBindings,
+			"mounts":       mounts,
 		}).Debug("Container has been started")
 	}
 
 	return err
 }
 
+// RemoveContainer removes a container by name, without removing its stack
+func RemoveContainer(containerName string) error {
+	dockerClient := getDockerClient()
+
+	err := dockerClient.ContainerRemove(
+		context.Background(), containerName, types.ContainerRemoveOptions{
+			RemoveVolumes: true,
+			Force:         true,
+		})
+	if err == nil {
+		log.WithFields(log.Fields{
+			"container": containerName,
+		}).Info("Container has been removed")
+	}
+
+	return err
+}
+
+// StartContainer starts a container by name, without starting its stack
+func StartContainer(containerName string) error {
+	dockerClient := getDockerClient()
+
+	err := dockerClient.ContainerStart(
+		context.Background(), containerName, types.ContainerStartOptions{})
+	if err == nil {
+		log.WithFields(log.Fields{
+			"container": containerName,
+		}).Info("Container has been started")
+	}
+
+	return err
+}
+
+// StopContainer stops a container by name, without stopping its stack
+func StopContainer(containerName string) error {
+	dockerClient := getDockerClient()
+
+	err := dockerClient.ContainerStop(context.Background(), containerName, nil)
+	if err == nil {
+		log.WithFields(log.Fields{
+			"container": containerName,
+		}).Info("Container has been stopped")
+	}
+
+	return err
+}
+
 // StartDockerContainer starts the stopped container
 func StartDockerContainer(image liferay.Image) error {
 	dockerClient := getDockerClient()
@@ -663,7 +1093,7 @@ func StartDockerContainer(image liferay.Image) error {
 	containers, err := PsFilterByLabel("lpn-type=" + image.GetType())
 
 	if len(containers) == 0 {
-		return errors.New("Error response from daemon: No such container: lpn-" + image.GetType())
+		return errors.New("Error response from daemon: No such container: " + image.GetContainerName())
 	}
 
 	for _, container := range containers {
@@ -695,34 +1125,156 @@ func StartDockerContainer(image liferay.Image) error {
 	return err
 }
 
-// StopDockerContainer stops the running container
-func StopDockerContainer(image liferay.Image) error {
-	dockerClient := getDockerClient()
+// DefaultStopTimeout time the portal is given to shut down gracefully, long enough for Tomcat to
+// undeploy the portal, before it's killed
+const DefaultStopTimeout = time.Minute
 
-	var err error
+// StopDockerContainer stops the stack of the image in order: first the portal, waiting for it to
+// shut down gracefully up to a timeout, then the rest of the containers of the stack, and finally
+// the database, so that the portal does not lose its connections while stopping
+func StopDockerContainer(image liferay.Image, timeout time.Duration) error {
+	dockerClient := getDockerClient()
 
 	containers, err := PsFilterByLabel("lpn-type=" + image.GetType())
+	if err != nil {
+		return err
+	}
 
 	if len(containers) == 0 {
-		return errors.New("Error response from daemon: No such container: lpn-" + image.GetType())
+		return errors.New("Error response from daemon: No such container: " + image.GetContainerName())
 	}
 
+	sidecars := []string{}
+	databases := []string{}
+
 	for _, container := range containers {
 		name := strings.TrimLeft(container.Names[0], "/")
-		err = dockerClient.ContainerStop(context.Background(), name, nil)
-		if err == nil {
-			log.WithFields(log.Fields{
-				"container": name,
-			}).Info("Container has been stopped")
+
+		if name == image.GetContainerName() {
+			continue
+		}
+
+		if _, ok := container.Labels["db-type"]; ok {
+			databases = append(databases, name)
+		} else {
+			sidecars = append(sidecars, name)
 		}
 	}
 
+	err = StopPortalContainer(image, timeout)
+	if err != nil {
+		log.WithFields(log.Fields{
+			"container": image.GetContainerName(),
+		}).Warn("The rest of the stack is kept running")
+		return err
+	}
+
+	for _, name := range sidecars {
+		stopErr := dockerClient.ContainerStop(context.Background(), name, nil)
+		err = reportStop(name, "Sidecar", stopErr, err)
+	}
+
+	for _, name := range databases {
+		stopErr := dockerClient.ContainerStop(context.Background(), name, nil)
+		err = reportStop(name, "Database", stopErr, err)
+	}
+
 	return err
 }
 
+// StopPortalContainer stops the portal container of the image, without stopping its stack, waiting
+// for it to shut down gracefully up to a timeout
+func StopPortalContainer(image liferay.Image, timeout time.Duration) error {
+	dockerClient := getDockerClient()
+
+	start := time.Now()
+
+	err := dockerClient.ContainerStop(context.Background(), image.GetContainerName(), &timeout)
+	if err != nil {
+		log.WithFields(log.Fields{
+			"container": image.GetContainerName(),
+			"error":     err,
+		}).Error("Could not stop the portal container")
+		return err
+	}
+
+	log.WithFields(log.Fields{
+		"container": image.GetContainerName(),
+		"elapsed":   time.Since(start).Round(time.Second).String(),
+	}).Info("Portal container has been stopped")
+
+	return nil
+}
+
+// reportStop logs the result of stopping a container of the stack, returning the first error
+func reportStop(containerName string, kind string, stopErr error, firstErr error) error {
+	if stopErr != nil {
+		log.WithFields(log.Fields{
+			"container": containerName,
+			"error":     stopErr,
+		}).Error("Could not stop the " + strings.ToLower(kind) + " container")
+
+		if firstErr == nil {
+			return stopErr
+		}
+
+		return firstErr
+	}
+
+	log.WithFields(log.Fields{
+		"container": containerName,
+	}).Info(kind + " container has been stopped")
+
+	return firstErr
+}
+
+// labelProperties label of the container storing the portal properties it was run with, one per line
+const labelProperties = "lpn-properties"
+
+// labelEnv label of the container storing the environment variables it was run with, one per line
+const labelEnv = "lpn-env"
+
+// labelProfile label of the containers storing the profile they were run with
+const labelProfile = "lpn-profile"
+
+// jvmOptionsEnvVar variable of the portal images with the options of the JVM
+const jvmOptionsEnvVar = "LIFERAY_JVM_OPTS"
+
+// RunOptions options a portal container is run with. Memory replaces the JVM options of the image,
+// and JVMOptions are appended to the ones of Memory, or to the ones of the image if it's empty. Env
+// and DbEnv override the environment variables set by lpn on the portal and database containers.
+// HotDeploy is a local directory mounted onto the deploy folder of the image. TransientProperties
+// override Properties, but they are not stored in the container, so they are only used once
+type RunOptions struct {
+	CPUs                float64
+	DbEnv               []string
+	DbMemLimit          int64
+	DebugPort           int
+	EnableDebug         bool
+	Env                 []string
+	GogoShellPort       int
+	HotDeploy           string
+	HTTPPort            int
+	JVMOptions          string
+	License             string
+	MemLimit            int64
+	Memory              string
+	Mounts              []liferay.Mount
+	Properties          []string
+	TransientProperties []string
+}
+
+// RunSettings settings a portal container was run with
+type RunSettings struct {
+	RunOptions
+	Datastore string
+	Image     string
+	Tag       string
+}
+
 // ContainerInstance simple model for a container
 type ContainerInstance struct {
-	ID     string `json:"id" binding:"required"`
-	Name   string `json:"name" binding:"required"`
-	Status string `json:"status" binding:"required"`
+	ID     string `json:"id" yaml:"id" binding:"required"`
+	Name   string `json:"name" yaml:"name" binding:"required"`
+	Status string `json:"status" yaml:"status" binding:"required"`
 }
@@ -0,0 +1,42 @@
+package docker
+
+import (
+	"testing"
+
+	types "github.com/docker/docker/api/types"
+	internal "github.com/mdelapenya/lpn/internal"
+	"github.com/stretchr/testify/assert"
+)
+
+func newProfileContainer(name string, profile string) types.Container {
+	labels := map[string]string{"lpn-type": "ce"}
+	if profile != "" {
+		labels[labelProfile] = profile
+	}
+
+	return types.Container{Names: []string{"/" + name}, Labels: labels}
+}
+
+func TestFilterProfileContainers(t *testing.T) {
+	containers := []types.Container{
+		newProfileContainer("lpn-ce", ""),
+		newProfileContainer("lpn-dxp", internal.DefaultProfile),
+		newProfileContainer("lpn-ce-customer", "customer"),
+		newProfileContainer("lpn-ce-upstream", "upstream"),
+	}
+
+	assert := assert.New(t)
+
+	customer := filterProfileContainers(containers, "customer")
+	assert.Len(customer, 1)
+	assert.Equal("/lpn-ce-customer", customer[0].Names[0])
+
+	// the containers without profile label were run before the profiles existed
+	defaults := filterProfileContainers(containers, internal.DefaultProfile)
+	assert.Len(defaults, 2)
+	assert.Equal("/lpn-ce", defaults[0].Names[0])
+	assert.Equal("/lpn-dxp", defaults[1].Names[0])
+
+	assert.Len(filterProfileContainers(containers, ""), 2)
+	assert.Len(filterProfileContainers(containers, "unknown"), 0)
+}
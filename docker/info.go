@@ -0,0 +1,192 @@
+package docker
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	types "github.com/docker/docker/api/types"
+	stdcopy "github.com/docker/docker/pkg/stdcopy"
+	liferay "github.com/mdelapenya/lpn/liferay"
+	log "github.com/sirupsen/logrus"
+)
+
+// ImageInfo information read from the configuration of a local image
+type ImageInfo struct {
+	Architecture string
+	Created      string
+	Env          map[string]string
+	ID           string
+	Labels       map[string]string
+	OS           string
+}
+
+// PortalInfo information queried to the portal running in a container. Empty values could not be
+// queried
+type PortalInfo struct {
+	PatchLevel string
+	Release    liferay.ReleaseInfo
+	ServerInfo map[string]string
+}
+
+// GetImageInfo reads the creation date, labels and environment of a local image
+func GetImageInfo(image liferay.Image) (ImageInfo, error) {
+	dockerClient := getDockerClient()
+
+	imageInspect, _, err := dockerClient.ImageInspectWithRaw(
+		context.Background(), strings.ReplaceAll(image.GetFullyQualifiedName(), "docker.io/", ""))
+	if err != nil {
+		return ImageInfo{}, err
+	}
+
+	info := ImageInfo{
+		Architecture: imageInspect.Architecture,
+		Created:      imageInspect.Created,
+		Env:          map[string]string{},
+		ID:           imageInspect.ID,
+		Labels:       map[string]string{},
+		OS:           imageInspect.Os,
+	}
+
+	if imageInspect.Config != nil {
+		for _, variable := range imageInspect.Config.Env {
+			kv := strings.SplitN(variable, "=", 2)
+			if len(kv) == 2 {
+				info.Env[kv[0]] = kv[1]
+			}
+		}
+
+		for key, value := range imageInspect.Config.Labels {
+			info.Labels[key] = value
+		}
+	}
+
+	return info, nil
+}
+
+// GetPortalInfo queries the portal running in the container of the image: the release from its
+// startup log line, Tomcat's ServerInfo, and the patch level, if the patching tool is present
+func GetPortalInfo(image liferay.Image) PortalInfo {
+	info := PortalInfo{
+		ServerInfo: map[string]string{},
+	}
+
+	release, err := findReleaseInfo(image.GetContainerName())
+	if err != nil {
+		log.WithFields(log.Fields{
+			"container": image.GetContainerName(),
+			"error":     err,
+		}).Debug("Could not read the release of the portal")
+	}
+	info.Release = release
+
+	serverInfoCmd := fmt.Sprintf(
+		`java -cp "$(ls -d %s/tomcat*/lib | head -n 1)/catalina.jar" org.apache.catalina.util.ServerInfo`,
+		image.GetLiferayHome())
+
+	output, err := execCommandOutput(image.GetContainerName(), image.GetUser(), []string{"sh", "-c", serverInfoCmd})
+	if err != nil {
+		log.WithFields(log.Fields{
+			"container": image.GetContainerName(),
+			"error":     err,
+		}).Debug("Could not query Tomcat's ServerInfo")
+	} else {
+		info.ServerInfo = liferay.ParseServerInfo(output)
+	}
+
+	patchingToolCmd := fmt.Sprintf("cd %s/patching-tool && ./patching-tool.sh info", image.GetLiferayHome())
+
+	output, err = execCommandOutput(image.GetContainerName(), image.GetUser(), []string{"sh", "-c", patchingToolCmd})
+	if err != nil {
+		log.WithFields(log.Fields{
+			"container": image.GetContainerName(),
+			"error":     err,
+		}).Debug("Could not query the patching tool")
+	} else {
+		info.PatchLevel = liferay.ParsePatchLevel(output)
+	}
+
+	return info
+}
+
+// execCommandOutput runs a command into the container as a user, returning its standard output.
+// It returns an error including the standard error if the command fails
+func execCommandOutput(containerName string, user string, cmd []string) (string, error) {
+	dockerClient := getDockerClient()
+
+	execConfig := types.ExecConfig{
+		User:         user,
+		AttachStderr: true,
+		AttachStdout: true,
+		Cmd:          cmd,
+	}
+
+	response, err := dockerClient.ContainerExecCreate(context.Background(), containerName, execConfig)
+	if err != nil {
+		return "", err
+	}
+
+	hijacked, err := dockerClient.ContainerExecAttach(context.Background(), response.ID, execConfig)
+	if err != nil {
+		return "", err
+	}
+	defer hijacked.Close()
+
+	var stdout, stderr bytes.Buffer
+	_, err = stdcopy.StdCopy(&stdout, &stderr, hijacked.Reader)
+	if err != nil {
+		return "", err
+	}
+
+	execInspect, err := dockerClient.ContainerExecInspect(context.Background(), response.ID)
+	if err != nil {
+		return "", err
+	}
+
+	if execInspect.ExitCode != 0 {
+		return "", fmt.Errorf(
+			"The command exited with code %d: %s", execInspect.ExitCode, strings.TrimSpace(stderr.String()))
+	}
+
+	return stdout.String(), nil
+}
+
+// findReleaseInfo reads the release of the portal from the first startup log line of the container.
+// The logs are scanned while they are demultiplexed, so that they are not held in memory
+func findReleaseInfo(containerName string) (liferay.ReleaseInfo, error) {
+	dockerClient := getDockerClient()
+
+	reader, err := dockerClient.ContainerLogs(
+		context.Background(), containerName, types.ContainerLogsOptions{ShowStdout: true, ShowStderr: true})
+	if err != nil {
+		return liferay.ReleaseInfo{}, err
+	}
+	defer reader.Close()
+
+	logs, writer := io.Pipe()
+	defer logs.Close()
+
+	// closing the pipe when returning makes the copy fail, finishing the goroutine
+	go func() {
+		_, err := stdcopy.StdCopy(writer, writer, reader)
+		writer.CloseWithError(err)
+	}()
+
+	scanner := bufio.NewScanner(logs)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	for scanner.Scan() {
+		if release, ok := liferay.ParseReleaseInfo(scanner.Text()); ok {
+			return release, nil
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return liferay.ReleaseInfo{}, err
+	}
+
+	return liferay.ReleaseInfo{}, fmt.Errorf("There is no startup line in the logs of %s", containerName)
+}
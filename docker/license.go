@@ -0,0 +1,139 @@
+package docker
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"path"
+	"path/filepath"
+
+	types "github.com/docker/docker/api/types"
+	liferay "github.com/mdelapenya/lpn/liferay"
+	log "github.com/sirupsen/logrus"
+)
+
+// LabelLicense label of the container storing the path of the activation key it was run with
+const LabelLicense = "lpn-license"
+
+// GetLicense returns the activation key installed in the container of the image, read back from
+// its deploy folder or, once the portal has deployed it, from the osgi/modules folder, and the
+// local path of the file the container was run with
+func GetLicense(image liferay.Image) (liferay.License, string, error) {
+	dockerClient := getDockerClient()
+
+	containerJSON, err := dockerClient.ContainerInspect(context.Background(), image.GetContainerName())
+	if err != nil {
+		return liferay.License{}, "", err
+	}
+
+	localPath, ok := containerJSON.Config.Labels[LabelLicense]
+	if !ok {
+		return liferay.License{}, "", fmt.Errorf(
+			"The container %s was not run with an activation key", image.GetContainerName())
+	}
+
+	fileName := filepath.Base(localPath)
+
+	for _, folder := range []string{image.GetDeployFolder(), image.GetLiferayHome() + "/osgi/modules"} {
+		content, err := readContainerFile(image.GetContainerName(), path.Join(folder, fileName))
+		if err != nil {
+			log.WithFields(log.Fields{
+				"container": image.GetContainerName(),
+				"folder":    folder,
+				"license":   fileName,
+				"error":     err,
+			}).Debug("The activation key is not in the folder")
+			continue
+		}
+
+		license, err := liferay.ParseLicense(content)
+
+		return license, localPath, err
+	}
+
+	return liferay.License{}, localPath, fmt.Errorf(
+		"The activation key %s is not installed in the container %s", fileName, image.GetContainerName())
+}
+
+// installLicense copies the activation key into the deploy folder of a created container, so
+// that the portal installs it when booting. The deploy folder is created if it does not exist, and
+// both are owned by the user running the portal
+func installLicense(image liferay.Image, containerID string, localPath string) error {
+	content, err := ioutil.ReadFile(localPath)
+	if err != nil {
+		return err
+	}
+
+	deployFolder := image.GetDeployFolder()
+
+	// the container is not running, so the ids of the user are read from its files
+	uid, gid, err := getCreatedContainerUserIDs(containerID, image.GetUser())
+	if err != nil {
+		log.WithFields(log.Fields{
+			"container": image.GetContainerName(),
+			"user":      image.GetUser(),
+			"error":     err,
+		}).Error("Could not read the ids of the user of the container")
+		return err
+	}
+
+	var buffer bytes.Buffer
+	tarWriter := tar.NewWriter(&buffer)
+
+	err = tarWriter.WriteHeader(&tar.Header{
+		Name:     path.Base(deployFolder) + "/",
+		Typeflag: tar.TypeDir,
+		Mode:     0755,
+		Uid:      uid,
+		Gid:      gid,
+	})
+	if err != nil {
+		return fmt.Errorf("Could not build TAR header: %v", err)
+	}
+
+	err = tarWriter.WriteHeader(&tar.Header{
+		Name: path.Base(deployFolder) + "/" + filepath.Base(localPath),
+		Mode: 0644,
+		Size: int64(len(content)),
+		Uid:  uid,
+		Gid:  gid,
+	})
+	if err != nil {
+		return fmt.Errorf("Could not build TAR header: %v", err)
+	}
+
+	_, err = tarWriter.Write(content)
+	if err != nil {
+		return err
+	}
+
+	err = tarWriter.Close()
+	if err != nil {
+		return err
+	}
+
+	dockerClient := getDockerClient()
+
+	err = dockerClient.CopyToContainer(
+		context.Background(), containerID, path.Dir(deployFolder),
+		&buffer, types.CopyToContainerOptions{AllowOverwriteDirWithFile: true})
+	if err != nil {
+		log.WithFields(log.Fields{
+			"container": image.GetContainerName(),
+			"deployDir": deployFolder,
+			"license":   localPath,
+			"error":     err,
+		}).Error("Could not install the activation key")
+		return err
+	}
+
+	log.WithFields(log.Fields{
+		"container": image.GetContainerName(),
+		"deployDir": deployFolder,
+		"license":   localPath,
+	}).Debug("Activation key installed")
+
+	return nil
+}
@@ -58,13 +58,13 @@ func (p PostgreSQL) GetPort() int {
 
 // GetRepository returns the repository for PostgreSQL
 func (p PostgreSQL) GetRepository() string {
-	return internal.LpnConfig.GetDbImageName("postgres")
+	return internal.LpnConfig.GetDbImageName("postgresql")
 }
 
 // GetTag returns the tag of the image
 func (p PostgreSQL) GetTag() string {
 	if p.Tag == "" {
-		return internal.LpnConfig.GetDbImageTag("postgres")
+		return internal.LpnConfig.GetDbImageTag("postgresql")
 	}
 
 	return p.Tag
@@ -0,0 +1,82 @@
+package docker
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"time"
+
+	types "github.com/docker/docker/api/types"
+	stdcopy "github.com/docker/docker/pkg/stdcopy"
+	liferay "github.com/mdelapenya/lpn/liferay"
+	log "github.com/sirupsen/logrus"
+)
+
+var startupRegexp = regexp.MustCompile(`Server startup in`)
+
+// upgradeErrorRegexp matches the upgrade exceptions, and the errors logged by the loggers of the
+// upgrade processes, as [DBUpgrader:123] or [UpgradeProcess:45]
+var upgradeErrorRegexp = regexp.MustCompile(`UpgradeException|(ERROR|FATAL) +\[[^\]]*\]\[\w*Upgrad\w*:\d+\]`)
+var upgradeRegexp = regexp.MustCompile(`(?i)upgrad|verif`)
+
+// FollowUpgrade follows the logs of the portal container, logging the progress of the database
+// upgrade, until the portal has started up. It returns an error if the upgrade fails, if the
+// container exits, or if the portal does not start up before the timeout
+func FollowUpgrade(image liferay.Image, timeout time.Duration) error {
+	dockerClient := getDockerClient()
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	reader, err := dockerClient.ContainerLogs(
+		ctx, image.GetContainerName(),
+		types.ContainerLogsOptions{ShowStdout: true, ShowStderr: true, Follow: true})
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+
+	pipeReader, pipeWriter := io.Pipe()
+	go func() {
+		_, err := stdcopy.StdCopy(pipeWriter, pipeWriter, reader)
+		pipeWriter.CloseWithError(err)
+	}()
+
+	scanner := bufio.NewScanner(pipeReader)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+
+		switch {
+		case upgradeErrorRegexp.MatchString(line):
+			log.WithFields(log.Fields{
+				"container": image.GetContainerName(),
+			}).Error(line)
+			return fmt.Errorf("The upgrade failed: %s", line)
+		case startupRegexp.MatchString(line):
+			log.WithFields(log.Fields{
+				"container": image.GetContainerName(),
+			}).Info(line)
+			return nil
+		case upgradeRegexp.MatchString(line):
+			log.WithFields(log.Fields{
+				"container": image.GetContainerName(),
+			}).Info(line)
+		default:
+			log.WithFields(log.Fields{
+				"container": image.GetContainerName(),
+			}).Debug(line)
+		}
+	}
+
+	if ctx.Err() == context.DeadlineExceeded {
+		return fmt.Errorf("The portal did not start up in %s", timeout)
+	}
+
+	return errors.New("The container stopped before the portal started up")
+}
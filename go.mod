@@ -18,4 +18,5 @@ require (
 	github.com/spf13/viper v1.4.0
 	github.com/stretchr/testify v1.2.2
 	github.com/vjeantet/jodaTime v0.0.0-20170816150230-be924ce213fb
+	gopkg.in/yaml.v2 v2.2.2
 )
@@ -4,7 +4,9 @@ import (
 	"os"
 	"os/user"
 	"path/filepath"
+	"sort"
 	"strings"
+	"time"
 
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/viper"
@@ -32,55 +34,167 @@ var dbImages = map[string]ImageConfig{
 		Image: "docker.io/mdelapenya/mysql-utf8",
 		Tag:   "5.7",
 	},
-	"postgres": {
+	"postgresql": {
 		Image: "postgres",
 		Tag:   "9.6-alpine",
 	},
 }
 var portalImages = map[string]ImageConfig{
 	"ce": {
-		Image: "liferay/portal",
-		Tag:   "7.0.6-ga7",
+		Description: "Liferay Portal CE",
+		Image:       "liferay/portal",
+		Tag:         "7.0.6-ga7",
+		LiferayHome: "/opt/liferay",
+		User:        "liferay",
+		DebugEnvVar: "LIFERAY_JPDA_ENABLED",
 	},
 	"commerce": {
-		Image: "liferay/commerce",
-		Tag:   "1.1.1",
+		Description: "Liferay Portal with Commerce",
+		Image:       "liferay/commerce",
+		Tag:         "1.1.1",
+		LiferayHome: "/opt/liferay",
+		User:        "liferay",
+		DebugEnvVar: "LIFERAY_JPDA_ENABLED",
 	},
 	"dxp": {
-		Image: "liferay/dxp",
-		Tag:   "7.0.10.8",
+		Description: "Liferay DXP",
+		Image:       "liferay/dxp",
+		Tag:         "7.0.10.8",
+		Licensed:    true,
+		LiferayHome: "/opt/liferay",
+		User:        "liferay",
+		DebugEnvVar: "LIFERAY_JPDA_ENABLED",
 	},
 	"nightly": {
-		Image: "mdelapenya/portal-snapshot",
-		Tag:   "master",
+		Description: "Liferay Portal Nightly Build",
+		Image:       "mdelapenya/portal-snapshot",
+		Tag:         "master",
+		DateTags:    true,
+		LiferayHome: "/opt/liferay",
+		User:        "liferay",
+		DebugEnvVar: "LIFERAY_JPDA_ENABLED",
 	},
 	"release": {
-		Image: "mdelapenya/liferay-portal",
-		Tag:   "latest",
+		Description: "Liferay Portal Release",
+		Image:       "mdelapenya/liferay-portal",
+		Tag:         "latest",
+		LiferayHome: "/liferay",
+		User:        "liferay",
+		DebugEnvVar: "DEBUG_MODE",
 	},
 }
 
-// ImageConfig image configuration
+// TagSourceHub tag source type for the Docker Hub API
+const TagSourceHub = "hub"
+
+// TagSourceRegistry tag source type for a Docker Registry v2 API
+const TagSourceRegistry = "registry"
+
+// ImageConfig image configuration. Database images only use the image and tag
 type ImageConfig struct {
-	Image string `yaml:"image"`
-	Tag   string `yaml:"tag"`
+	ContainerName string          `mapstructure:"containerName" yaml:"containerName,omitempty"`
+	DateTags      bool            `mapstructure:"dateTags" yaml:"dateTags,omitempty"`
+	DebugEnvVar   string          `mapstructure:"debugEnvVar" yaml:"debugEnvVar,omitempty"`
+	DeployFolder  string          `mapstructure:"deployFolder" yaml:"deployFolder,omitempty"`
+	Description   string          `yaml:"description,omitempty"`
+	Image         string          `yaml:"image"`
+	LiferayHome   string          `mapstructure:"liferayHome" yaml:"liferayHome,omitempty"`
+	Licensed      bool            `yaml:"licensed,omitempty"`
+	Tag           string          `yaml:"tag"`
+	TagSource     TagSourceConfig `mapstructure:"tagSource" yaml:"tagSource,omitempty"`
+	User          string          `yaml:"user,omitempty"`
+}
+
+// TagSourceConfig configuration of the source the tags of an image are read from
+type TagSourceConfig struct {
+	Type     string `yaml:"type,omitempty"`
+	URL      string `yaml:"url,omitempty"`
+	Username string `yaml:"username,omitempty"`
+	Password string `yaml:"password,omitempty"`
+	Token    string `yaml:"token,omitempty"`
 }
 
 // ImagesConfig image configuration
 type ImagesConfig struct {
-	Db     map[string]ImageConfig `mapstructure:"db"`
-	Portal map[string]ImageConfig `mapstructure:"portal"`
+	Db     map[string]ImageConfig `mapstructure:"db" yaml:"db"`
+	Portal map[string]ImageConfig `mapstructure:"portal" yaml:"portal"`
+}
+
+// defaultTagsCacheTTL time the tags listings are cached when not configured
+const defaultTagsCacheTTL = "1h"
+
+// CacheConfig cache configuration
+type CacheConfig struct {
+	Tags CacheEntryConfig `mapstructure:"tags" yaml:"tags"`
+}
+
+// CacheEntryConfig configuration of a type of cached data
+type CacheEntryConfig struct {
+	TTL string `mapstructure:"ttl" yaml:"ttl"`
+}
+
+// defaultDateTagsLookbackDays days to look back for date tags when not configured
+const defaultDateTagsLookbackDays = 7
+
+// DateTagsConfig configuration of the images tagged by date, as nightly builds
+type DateTagsConfig struct {
+	LookbackDays int `mapstructure:"lookbackDays" yaml:"lookbackDays"`
+}
+
+// ResourcesConfig default resource limits of the containers. Empty or zero values do not limit them
+type ResourcesConfig struct {
+	CPUs       float64 `mapstructure:"cpus" yaml:"cpus"`
+	DbMemLimit string  `mapstructure:"dbMemLimit" yaml:"dbMemLimit"`
+	MemLimit   string  `mapstructure:"memLimit" yaml:"memLimit"`
 }
 
 // LPNConfig tool configuration
 type LPNConfig struct {
-	Container NamesConfig  `mapstructure:"container"`
-	Images    ImagesConfig `mapstructure:"images"`
+	Cache     CacheConfig     `mapstructure:"cache" yaml:"cache"`
+	Container NamesConfig     `mapstructure:"container" yaml:"container"`
+	DateTags  DateTagsConfig  `mapstructure:"dateTags" yaml:"dateTags"`
+	Images    ImagesConfig    `mapstructure:"images" yaml:"images"`
+	Resources ResourcesConfig `mapstructure:"resources" yaml:"resources"`
+	Version   int             `mapstructure:"version" yaml:"version"`
+}
+
+// GetDateTagsLookbackDays number of days to look back for the most recent date tag
+func (c *LPNConfig) GetDateTagsLookbackDays() int {
+	if c.DateTags.LookbackDays <= 0 {
+		return defaultDateTagsLookbackDays
+	}
+
+	return c.DateTags.LookbackDays
 }
 
-// GetDbContainerName name of the container for databases
+// GetTagsCacheTTL time the tags listings are cached, as a duration (i.e. 1h or 30m)
+func (c *LPNConfig) GetTagsCacheTTL() time.Duration {
+	ttl := c.Cache.Tags.TTL
+	if ttl == "" {
+		ttl = defaultTagsCacheTTL
+	}
+
+	duration, err := time.ParseDuration(ttl)
+	if err != nil {
+		log.WithFields(log.Fields{
+			"ttl":   ttl,
+			"error": err,
+		}).Warn("The TTL of the tags cache is not a valid duration. Using the default one")
+
+		duration, _ = time.ParseDuration(defaultTagsCacheTTL)
+	}
+
+	return duration
+}
+
+// GetDbContainerName name of the container for databases, defaulting to "db-" plus the type. The
+// profile is added to the name, as for the portal containers
 func (c *LPNConfig) GetDbContainerName(t string) string {
-	return c.Container.Names.Db[t]
+	if name := c.Container.Names.Db[t]; name != "" {
+		return getProfileContainerName(name)
+	}
+
+	return getProfileContainerName("db-" + t)
 }
 
 // GetDbImageName name of the image used to run the portal
@@ -93,6 +207,11 @@ func (c *LPNConfig) GetDbImageTag(t string) string {
 	return c.Images.Db[t].Tag
 }
 
+// GetPortalImage configuration of the image used to run the portal
+func (c *LPNConfig) GetPortalImage(t string) ImageConfig {
+	return c.Images.Portal[t]
+}
+
 // GetPortalImageName name of the image used to run the portal
 func (c *LPNConfig) GetPortalImageName(t string) string {
 	return c.Images.Portal[t].Image
@@ -103,29 +222,66 @@ func (c *LPNConfig) GetPortalImageTag(t string) string {
 	return c.Images.Portal[t].Tag
 }
 
-// GetPortalContainerName name of the container for portal
+// GetPortalImageTagSource source of the tags of the image used to run the portal
+func (c *LPNConfig) GetPortalImageTagSource(t string) TagSourceConfig {
+	return c.Images.Portal[t].TagSource
+}
+
+// GetPortalContainerName name of the container for portal, read from the image configuration,
+// then from the container names configuration, and defaulting to "lpn-" plus the type. The
+// profile is added to the name, so that the containers of the profiles do not collide
 func (c *LPNConfig) GetPortalContainerName(t string) string {
-	return c.Container.Names.Portal[t]
+	if name := c.Images.Portal[t].ContainerName; name != "" {
+		return getProfileContainerName(name)
+	}
+
+	if name := c.Container.Names.Portal[t]; name != "" {
+		return getProfileContainerName(name)
+	}
+
+	return getProfileContainerName("lpn-" + t)
+}
+
+// GetPortalTypes sorted names of the configured portal image types
+func (c *LPNConfig) GetPortalTypes() []string {
+	types := []string{}
+	for t := range c.Images.Portal {
+		types = append(types, t)
+	}
+
+	sort.Strings(types)
+
+	return types
+}
+
+// IsPortalType returns if the type is a configured portal image type
+func (c *LPNConfig) IsPortalType(t string) bool {
+	_, ok := c.Images.Portal[t]
+	return ok
 }
 
 // NamesConfig container configuration
 type NamesConfig struct {
-	Names NameConfig `mapstructure:"names"`
+	Names NameConfig `mapstructure:"names" yaml:"names"`
 }
 
 // NameConfig container configuration
 type NameConfig struct {
-	Db     map[string]string `mapstructure:"db"`
-	Portal map[string]string `mapstructure:"portal"`
+	Db     map[string]string `mapstructure:"db" yaml:"db"`
+	Portal map[string]string `mapstructure:"portal" yaml:"portal"`
+}
+
+// GetBuiltInPortalImage returns the built-in configuration of a portal image type, which is empty
+// for the types defined in the configuration file only
+func GetBuiltInPortalImage(t string) ImageConfig {
+	return portalImages[t]
 }
 
 // CheckWorkspace creates this tool workspace under user's home, in a hidden directory named ".lpn"
 func CheckWorkspace() {
 	ConfigureLogger(os.Getenv("LPN_LOG_LEVEL"))
 
-	usr, _ := user.Current()
-
-	w := filepath.Join(usr.HomeDir, ".lpn")
+	w := getLpnHome()
 
 	if _, err := os.Stat(w); os.IsNotExist(err) {
 		err = os.MkdirAll(w, 0755)
@@ -136,14 +292,24 @@ func CheckWorkspace() {
 		log.Println("lpn workdir created at " + w)
 	}
 
-	LpnWorkspace = w
+	// the profile of the command line is selected when running the command, as it could not exist
+	err := UseProfile(DefaultProfile, false)
+	if err != nil {
+		log.Fatalf("Cannot read the configuration of the default profile: %v", err)
+	}
+}
 
-	LpnConfig = NewConfig(w)
+// getLpnHome returns the workspace of lpn under user's home, which is the workspace of the default
+// profile
+func getLpnHome() string {
+	usr, _ := user.Current()
+
+	return filepath.Join(usr.HomeDir, ".lpn")
 }
 
 // ConfigureLogger defines two logger settings:
-// 	1. log format including timestamp (true or false)
-// 	2. log level, where valid values are TRACE, DEBUG, INFO, WARN, ERROR, FATAL, PANIC
+//  1. log format including timestamp (true or false)
+//  2. log level, where valid values are TRACE, DEBUG, INFO, WARN, ERROR, FATAL, PANIC
 func ConfigureLogger(logLevel string) {
 	includeTimestamp := os.Getenv("LPN_LOG_INCLUDE_TIMESTAMP")
 	fullTimestamp := (strings.ToUpper(includeTimestamp) == "TRUE")
@@ -197,9 +363,18 @@ func initConfigFile(workspace string, configFile string, defaults map[string]int
 	return f
 }
 
-// NewConfig returns a new configuration
-func NewConfig(workspace string) *LPNConfig {
-	lpnConfig, err := readConfig(workspace, fileName, map[string]interface{}{
+// getConfigDefaults returns the built-in configuration, written to the configuration file when it's
+// created
+func getConfigDefaults() map[string]interface{} {
+	return map[string]interface{}{
+		"cache": map[string]interface{}{
+			"tags": map[string]interface{}{
+				"ttl": defaultTagsCacheTTL,
+			},
+		},
+		"dateTags": map[string]interface{}{
+			"lookbackDays": defaultDateTagsLookbackDays,
+		},
 		"container": map[string]interface{}{
 			"names": map[string]interface{}{
 				"db":     dbContainerNames,
@@ -210,7 +385,18 @@ func NewConfig(workspace string) *LPNConfig {
 			"db":     dbImages,
 			"portal": portalImages,
 		},
-	})
+		"resources": map[string]interface{}{
+			"cpus":       0,
+			"dbMemLimit": "",
+			"memLimit":   "",
+		},
+		"version": configVersion,
+	}
+}
+
+// NewConfig returns a new configuration
+func NewConfig(workspace string) *LPNConfig {
+	lpnConfig, err := readConfig(workspace, fileName, getConfigDefaults())
 	if err != nil {
 		log.Fatalf("Error when reading config: %v\n", err)
 	}
@@ -221,21 +407,92 @@ func NewConfig(workspace string) *LPNConfig {
 func readConfig(
 	workspace string, configFile string, defaults map[string]interface{}) (LPNConfig, error) {
 
+	configFilePath := filepath.Join(workspace, configFile)
+
+	// the file is set, instead of searched, as the workspace changes with the profile
 	viper.SetConfigType("yaml")
-	viper.SetConfigName("config")
-	viper.AddConfigPath(workspace)
+	viper.SetConfigFile(configFilePath)
+
+	if _, err := os.Stat(configFilePath); os.IsNotExist(err) {
+		initConfigFile(workspace, configFile, defaults)
+	}
 
 	err := viper.ReadInConfig()
 	if err != nil {
-		initConfigFile(workspace, configFile, defaults)
-		viper.ReadInConfig()
+		log.WithFields(log.Fields{
+			"configFile": configFilePath,
+			"error":      err,
+		}).Fatal("Cannot read the configuration file. Please fix it")
 	}
 
+	err = migrateConfigFile()
+	if err != nil {
+		log.WithFields(log.Fields{
+			"configFile": GetConfigFile(),
+			"error":      err,
+		}).Fatal("Cannot migrate the configuration file to the current version")
+	}
+
+	// the migrated file is read again
+	viper.ReadInConfig()
+
 	var lpnConfig LPNConfig
 	err = viper.Unmarshal(&lpnConfig)
 	if err != nil {
 		log.Fatalf("Unable to decode configuration into struct, %v", err)
 	}
 
+	lpnConfig.Images.Portal = mergePortalImages(lpnConfig.Images.Portal, portalImages, viper.IsSet)
+
 	return lpnConfig, err
 }
+
+// mergePortalImages adds the built-in portal image types to the configured ones, filling the
+// properties a configured built-in type does not define, as config files written by older
+// versions of lpn. The isSet function tells if a key is present in the config file
+func mergePortalImages(
+	configured map[string]ImageConfig, builtIn map[string]ImageConfig,
+	isSet func(key string) bool) map[string]ImageConfig {
+
+	merged := map[string]ImageConfig{}
+	for t, image := range configured {
+		merged[t] = image
+	}
+
+	for t, defaults := range builtIn {
+		image, ok := merged[t]
+		if !ok {
+			merged[t] = defaults
+			continue
+		}
+
+		if image.DebugEnvVar == "" {
+			image.DebugEnvVar = defaults.DebugEnvVar
+		}
+		if image.Description == "" {
+			image.Description = defaults.Description
+		}
+		if image.Image == "" {
+			image.Image = defaults.Image
+		}
+		if image.LiferayHome == "" {
+			image.LiferayHome = defaults.LiferayHome
+		}
+		if image.Tag == "" {
+			image.Tag = defaults.Tag
+		}
+		if image.User == "" {
+			image.User = defaults.User
+		}
+		if !isSet("images.portal." + t + ".datetags") {
+			image.DateTags = defaults.DateTags
+		}
+		if !isSet("images.portal." + t + ".licensed") {
+			image.Licensed = defaults.Licensed
+		}
+
+		merged[t] = image
+	}
+
+	return merged
+}
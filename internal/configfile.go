@@ -0,0 +1,457 @@
+package internal
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// GetConfigFile returns the path of the configuration file
+func GetConfigFile() string {
+	if configFile := viper.ConfigFileUsed(); configFile != "" {
+		return configFile
+	}
+
+	return filepath.Join(LpnWorkspace, fileName)
+}
+
+// GetConfigValues returns the effective configuration, including the built-in values, flattened
+// into keys separated by dots, as images.portal.ce.tag
+func GetConfigValues() (map[string]interface{}, error) {
+	values, err := flattenConfig(LpnConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	defaults, err := flattenConfig(getConfigDefaults())
+	if err != nil {
+		return nil, err
+	}
+
+	// the empty values of the keys with a built-in value are replaced by it, as the configuration does
+	for key, value := range values {
+		if defaultValue, ok := defaults[key]; ok && isEmptyConfigValue(value) {
+			values[key] = defaultValue
+		}
+	}
+
+	return values, nil
+}
+
+// GetConfigValue returns the effective value of a key of the configuration. If the key contains
+// other keys, as images.portal.ce, the values of all of them are returned
+func GetConfigValue(key string) (map[string]interface{}, error) {
+	values, err := GetConfigValues()
+	if err != nil {
+		return nil, err
+	}
+
+	result := map[string]interface{}{}
+	for k, value := range values {
+		if strings.EqualFold(k, key) || strings.HasPrefix(strings.ToLower(k), strings.ToLower(key)+".") {
+			result[k] = value
+		}
+	}
+
+	if len(result) == 0 {
+		return nil, fmt.Errorf("%s is not set in the configuration", key)
+	}
+
+	return result, nil
+}
+
+// SetConfigValue validates the value of a key against the schema of the configuration, and writes
+// it to the configuration file, which is read again
+func SetConfigValue(key string, value string) error {
+	canonicalKey, typedValue, err := ValidateConfigValue(key, value, LpnConfig.GetPortalTypes())
+	if err != nil {
+		return err
+	}
+
+	if canonicalKey == "version" {
+		return errors.New("version is managed by lpn, which migrates the configuration file when it changes")
+	}
+
+	content, err := readConfigFile()
+	if err != nil {
+		return err
+	}
+
+	content = setConfigKey(content, strings.Split(canonicalKey, "."), typedValue)
+
+	return writeConfigFile(content)
+}
+
+// UnsetConfigValue removes a key from the configuration file, which is read again. The built-in
+// value of the key, if any, is used from then on
+func UnsetConfigValue(key string) error {
+	content, err := readConfigFile()
+	if err != nil {
+		return err
+	}
+
+	content, ok := unsetConfigKey(content, strings.Split(key, "."))
+	if !ok {
+		return fmt.Errorf("%s is not set in the configuration file", key)
+	}
+
+	return writeConfigFile(content)
+}
+
+// ResetConfig writes the built-in value of a key to the configuration file, or removes it if it has
+// no built-in value. If the key is empty, the whole file is backed up and written with the built-in
+// values
+func ResetConfig(key string) error {
+	defaults, err := flattenConfig(getConfigDefaults())
+	if err != nil {
+		return err
+	}
+
+	if key == "" {
+		content, err := toMapSlice(getConfigDefaults())
+		if err != nil {
+			return err
+		}
+
+		_, err = BackupConfigFile()
+		if err != nil {
+			return err
+		}
+
+		return writeConfigFile(content)
+	}
+
+	found := false
+	for k, value := range defaults {
+		if strings.EqualFold(k, key) || strings.HasPrefix(strings.ToLower(k), strings.ToLower(key)+".") {
+			found = true
+
+			err := SetConfigValue(k, fmt.Sprint(value))
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	if !found {
+		return UnsetConfigValue(key)
+	}
+
+	return nil
+}
+
+// BackupConfigFile copies the configuration file next to it, adding the current time to its name,
+// and returns the path of the copy
+func BackupConfigFile() (string, error) {
+	configFile := GetConfigFile()
+
+	bytes, err := ioutil.ReadFile(configFile)
+	if err != nil {
+		return "", err
+	}
+
+	backup := configFile + "." + time.Now().Format("20060102150405") + ".bak"
+
+	return backup, ioutil.WriteFile(backup, bytes, 0600)
+}
+
+// ReplaceConfigFile validates the content of a configuration file, and writes it as is, keeping
+// its comments, if there are no errors. The configuration is read again
+func ReplaceConfigFile(content []byte) []error {
+	errs := ValidateConfigFile(content)
+	if len(errs) > 0 {
+		return errs
+	}
+
+	err := writeConfigBytes(content)
+	if err != nil {
+		return []error{err}
+	}
+
+	return nil
+}
+
+// ValidateConfig validates the configuration file against the schema, returning an error per
+// offending key
+func ValidateConfig() []error {
+	bytes, err := ioutil.ReadFile(GetConfigFile())
+	if err != nil {
+		return []error{err}
+	}
+
+	return ValidateConfigFile(bytes)
+}
+
+// ValidateConfigFile validates the content of a configuration file against the schema, returning
+// an error per offending key
+func ValidateConfigFile(content []byte) []error {
+	var mapSlice yaml.MapSlice
+
+	err := yaml.Unmarshal(content, &mapSlice)
+	if err != nil {
+		return []error{err}
+	}
+
+	// viper keeps one of the keys differing in case only, so they are duplicated too
+	errs := findDuplicateKeys(mapSlice, "")
+
+	values := map[string]interface{}{}
+	flattenMapSlice(mapSlice, "", values)
+
+	portalTypes := map[string]bool{}
+	for t := range portalImages {
+		portalTypes[t] = true
+	}
+
+	for key := range values {
+		segments := strings.Split(key, ".")
+		if len(segments) > 3 && strings.EqualFold(segments[0], "images") && strings.EqualFold(segments[1], "portal") {
+			portalTypes[segments[2]] = true
+		}
+	}
+
+	types := []string{}
+	for t := range portalTypes {
+		types = append(types, t)
+	}
+
+	sort.Strings(types)
+
+	keys := []string{}
+	for key := range values {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		_, _, err := ValidateConfigValue(key, fmt.Sprint(values[key]), types)
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	// the portal types defined in the file only require their image and tag, as the built-in types
+	// take the missing ones from the built-in values
+	for _, t := range types {
+		if _, ok := portalImages[t]; ok {
+			continue
+		}
+
+		for _, required := range []string{"image", "tag"} {
+			key := "images.portal." + t + "." + required
+			if !hasKey(values, key) {
+				errs = append(errs, fmt.Errorf("%s: the key is required by the portal types", key))
+			}
+		}
+	}
+
+	return errs
+}
+
+// findDuplicateKeys returns an error per key present more than once in the ordered map, regardless
+// of its case
+func findDuplicateKeys(mapSlice yaml.MapSlice, prefix string) []error {
+	errs := []error{}
+	seen := map[string]bool{}
+
+	for _, item := range mapSlice {
+		key := prefix + fmt.Sprint(item.Key)
+
+		if seen[strings.ToLower(key)] {
+			errs = append(errs, fmt.Errorf("%s: the key is duplicated", key))
+		}
+
+		seen[strings.ToLower(key)] = true
+
+		if child, ok := item.Value.(yaml.MapSlice); ok {
+			errs = append(errs, findDuplicateKeys(child, key+".")...)
+		}
+	}
+
+	return errs
+}
+
+// isEmptyConfigValue returns if a value of the configuration is the zero value of its type, which
+// the configuration does not distinguish from a missing one. Booleans are always set
+func isEmptyConfigValue(value interface{}) bool {
+	switch v := value.(type) {
+	case string:
+		return v == ""
+	case int:
+		return v == 0
+	case float64:
+		return v == 0
+	}
+
+	return false
+}
+
+func hasKey(values map[string]interface{}, key string) bool {
+	for k := range values {
+		if strings.EqualFold(k, key) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// flattenConfig returns the values of a configuration, serialised with the keys of the file,
+// flattened into keys separated by dots
+func flattenConfig(config interface{}) (map[string]interface{}, error) {
+	mapSlice, err := toMapSlice(config)
+	if err != nil {
+		return nil, err
+	}
+
+	values := map[string]interface{}{}
+	flattenMapSlice(mapSlice, "", values)
+
+	return values, nil
+}
+
+func flattenMapSlice(mapSlice yaml.MapSlice, prefix string, values map[string]interface{}) {
+	for _, item := range mapSlice {
+		key := prefix + fmt.Sprint(item.Key)
+
+		// the empty keys carry no configuration
+		if child, ok := item.Value.(yaml.MapSlice); ok {
+			flattenMapSlice(child, key+".", values)
+			continue
+		}
+
+		if item.Value != nil {
+			values[key] = item.Value
+		}
+	}
+}
+
+// toMapSlice converts a value into the ordered map of its YAML serialisation
+func toMapSlice(value interface{}) (yaml.MapSlice, error) {
+	bytes, err := yaml.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+
+	var mapSlice yaml.MapSlice
+	err = yaml.Unmarshal(bytes, &mapSlice)
+
+	return mapSlice, err
+}
+
+// readConfigFile reads the configuration file as an ordered map, keeping the order and the case of
+// its keys, which viper does not keep when writing it
+func readConfigFile() (yaml.MapSlice, error) {
+	bytes, err := ioutil.ReadFile(GetConfigFile())
+	if err != nil {
+		return nil, err
+	}
+
+	var mapSlice yaml.MapSlice
+	err = yaml.Unmarshal(bytes, &mapSlice)
+
+	return mapSlice, err
+}
+
+// writeConfigFile writes the configuration file, and reads it again into the configuration
+func writeConfigFile(content yaml.MapSlice) error {
+	bytes, err := yaml.Marshal(content)
+	if err != nil {
+		return err
+	}
+
+	return writeConfigBytes(bytes)
+}
+
+// writeConfigBytes writes the content of the configuration file, and reads it again into the
+// configuration
+func writeConfigBytes(bytes []byte) error {
+	err := saveConfigBytes(bytes)
+	if err != nil {
+		return err
+	}
+
+	LpnConfig = NewConfig(LpnWorkspace)
+
+	return nil
+}
+
+// saveConfigBytes writes the content of the configuration file, keeping its permissions
+func saveConfigBytes(bytes []byte) error {
+	configFile := GetConfigFile()
+
+	mode := os.FileMode(0644)
+	if info, err := os.Stat(configFile); err == nil {
+		mode = info.Mode()
+	}
+
+	return ioutil.WriteFile(configFile, bytes, mode)
+}
+
+// setConfigKey sets the value of the key in the ordered map, creating the missing intermediate
+// keys. The existing keys are matched regardless of their case, and renamed as the key
+func setConfigKey(mapSlice yaml.MapSlice, segments []string, value interface{}) yaml.MapSlice {
+	for i, item := range mapSlice {
+		if !strings.EqualFold(fmt.Sprint(item.Key), segments[0]) {
+			continue
+		}
+
+		mapSlice[i].Key = segments[0]
+
+		if len(segments) == 1 {
+			mapSlice[i].Value = value
+			return mapSlice
+		}
+
+		child, _ := item.Value.(yaml.MapSlice)
+		mapSlice[i].Value = setConfigKey(child, segments[1:], value)
+
+		return mapSlice
+	}
+
+	if len(segments) == 1 {
+		return append(mapSlice, yaml.MapItem{Key: segments[0], Value: value})
+	}
+
+	return append(mapSlice, yaml.MapItem{Key: segments[0], Value: setConfigKey(nil, segments[1:], value)})
+}
+
+// unsetConfigKey removes the key from the ordered map, and the intermediate keys left empty,
+// returning false if the key is not present
+func unsetConfigKey(mapSlice yaml.MapSlice, segments []string) (yaml.MapSlice, bool) {
+	for i, item := range mapSlice {
+		if !strings.EqualFold(fmt.Sprint(item.Key), segments[0]) {
+			continue
+		}
+
+		if len(segments) > 1 {
+			child, ok := item.Value.(yaml.MapSlice)
+			if !ok {
+				return mapSlice, false
+			}
+
+			child, ok = unsetConfigKey(child, segments[1:])
+			if !ok {
+				return mapSlice, false
+			}
+
+			if len(child) > 0 {
+				mapSlice[i].Value = child
+				return mapSlice, true
+			}
+		}
+
+		return append(mapSlice[:i], mapSlice[i+1:]...), true
+	}
+
+	return mapSlice, false
+}
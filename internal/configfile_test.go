@@ -0,0 +1,264 @@
+package internal
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// newConfigWorkspace reads the configuration from a temporary workspace, whose configuration file
+// has the content passed, or the built-in values if it's empty
+func newConfigWorkspace(t *testing.T, content string) func() {
+	workspace, err := ioutil.TempDir("", "lpn-config")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if content != "" {
+		err = ioutil.WriteFile(filepath.Join(workspace, fileName), []byte(content), 0644)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	previousWorkspace, previousConfig := LpnWorkspace, LpnConfig
+
+	LpnWorkspace = workspace
+	LpnConfig = NewConfig(workspace)
+
+	return func() {
+		LpnWorkspace, LpnConfig = previousWorkspace, previousConfig
+		os.RemoveAll(workspace)
+	}
+}
+
+func newMapSlice(t *testing.T, content string) yaml.MapSlice {
+	var mapSlice yaml.MapSlice
+
+	err := yaml.Unmarshal([]byte(content), &mapSlice)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return mapSlice
+}
+
+func TestSetConfigKey(t *testing.T) {
+	content := newMapSlice(t, "images:\n  portal:\n    ce:\n      tag: 7.0.6-ga7\n")
+
+	content = setConfigKey(content, []string{"images", "portal", "ce", "tag"}, "7.2.0-ga1")
+
+	assert := assert.New(t)
+
+	value, ok := getConfigKey(content, []string{"images", "portal", "ce", "tag"})
+	assert.True(ok)
+	assert.Equal("7.2.0-ga1", value)
+}
+
+func TestSetConfigKeyCreatesIntermediateKeys(t *testing.T) {
+	content := newMapSlice(t, "version: 2\n")
+
+	content = setConfigKey(content, []string{"images", "portal", "mine", "image"}, "myorg/portal")
+
+	assert := assert.New(t)
+
+	value, ok := getConfigKey(content, []string{"images", "portal", "mine", "image"})
+	assert.True(ok)
+	assert.Equal("myorg/portal", value)
+	assert.Equal("version", content[0].Key)
+}
+
+func TestSetConfigKeyRenamesKeysDifferingInCase(t *testing.T) {
+	content := newMapSlice(t, "images:\n  portal:\n    ce:\n      liferayhome: /liferay\n")
+
+	content = setConfigKey(content, []string{"images", "portal", "ce", "liferayHome"}, "/opt/liferay")
+
+	bytes, err := yaml.Marshal(content)
+
+	assert := assert.New(t)
+
+	assert.Nil(err)
+	assert.Contains(string(bytes), "liferayHome: /opt/liferay")
+	assert.NotContains(string(bytes), "liferayhome")
+}
+
+func TestUnsetConfigKey(t *testing.T) {
+	content := newMapSlice(t, "images:\n  portal:\n    ce:\n      tag: 7.0.6-ga7\n      user: liferay\n")
+
+	content, ok := unsetConfigKey(content, []string{"images", "portal", "CE", "Tag"})
+
+	assert := assert.New(t)
+
+	assert.True(ok)
+
+	_, ok = getConfigKey(content, []string{"images", "portal", "ce", "tag"})
+	assert.False(ok)
+
+	value, ok := getConfigKey(content, []string{"images", "portal", "ce", "user"})
+	assert.True(ok)
+	assert.Equal("liferay", value)
+}
+
+func TestUnsetConfigKeyRemovesEmptyKeys(t *testing.T) {
+	content := newMapSlice(t, "version: 2\nimages:\n  portal:\n    mine:\n      image: myorg/portal\n")
+
+	content, ok := unsetConfigKey(content, []string{"images", "portal", "mine", "image"})
+
+	assert := assert.New(t)
+
+	assert.True(ok)
+	assert.Len(content, 1)
+	assert.Equal("version", content[0].Key)
+}
+
+func TestUnsetConfigKeyNotPresent(t *testing.T) {
+	content := newMapSlice(t, "images:\n  portal:\n    ce:\n      tag: 7.0.6-ga7\n")
+
+	_, ok := unsetConfigKey(content, []string{"images", "portal", "ce", "user"})
+
+	assert.False(t, ok)
+}
+
+func TestFindDuplicateKeys(t *testing.T) {
+	content := newMapSlice(t, "images:\n  portal:\n    ce:\n      tag: 7.0.6-ga7\n    CE:\n      tag: 7.2.0-ga1\n")
+
+	errs := findDuplicateKeys(content, "")
+
+	assert := assert.New(t)
+
+	assert.Len(errs, 1)
+	assert.Contains(errs[0].Error(), "images.portal.CE")
+}
+
+func TestFindDuplicateKeysNone(t *testing.T) {
+	content := newMapSlice(t, "images:\n  portal:\n    ce:\n      tag: 7.0.6-ga7\n    dxp:\n      tag: 7.2.10-dxp-1\n")
+
+	assert.Len(t, findDuplicateKeys(content, ""), 0)
+}
+
+func TestIsEmptyConfigValue(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.True(isEmptyConfigValue(""))
+	assert.True(isEmptyConfigValue(0))
+	assert.True(isEmptyConfigValue(float64(0)))
+	assert.False(isEmptyConfigValue(1.5))
+	assert.False(isEmptyConfigValue(false))
+	assert.False(isEmptyConfigValue("lpn-ce"))
+}
+
+func TestGetConfigValuesReplacesEmptyValues(t *testing.T) {
+	cleanUp := newConfigWorkspace(t, "version: 2\ncontainer:\n  names:\n    portal:\n      ce: \"\"\n")
+	defer cleanUp()
+
+	values, err := GetConfigValues()
+
+	assert := assert.New(t)
+
+	assert.Nil(err)
+	assert.Equal("lpn-ce", values["container.names.portal.ce"])
+	assert.Equal(portalImages["dxp"].Image, values["images.portal.dxp.image"])
+}
+
+func TestGetConfigValue(t *testing.T) {
+	cleanUp := newConfigWorkspace(t, "")
+	defer cleanUp()
+
+	values, err := GetConfigValue("Images.Portal.CE")
+
+	assert := assert.New(t)
+
+	assert.Nil(err)
+	assert.Equal(portalImages["ce"].Tag, values["images.portal.ce.tag"])
+
+	for key := range values {
+		assert.True(strings.HasPrefix(key, "images.portal.ce."))
+	}
+
+	_, err = GetConfigValue("images.portal.unknown")
+	assert.NotNil(err)
+}
+
+func TestSetConfigValue(t *testing.T) {
+	cleanUp := newConfigWorkspace(t, "")
+	defer cleanUp()
+
+	err := SetConfigValue("images.portal.ce.tag", "7.2.0-ga1")
+
+	assert := assert.New(t)
+
+	assert.Nil(err)
+	assert.Equal("7.2.0-ga1", LpnConfig.GetPortalImageTag("ce"))
+
+	err = SetConfigValue("resources.cpus", "many")
+	assert.NotNil(err)
+
+	err = SetConfigValue("version", "1")
+	assert.NotNil(err)
+}
+
+func TestResetConfigKey(t *testing.T) {
+	cleanUp := newConfigWorkspace(t, "")
+	defer cleanUp()
+
+	err := SetConfigValue("images.portal.ce.tag", "7.2.0-ga1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = ResetConfig("images.portal.ce.tag")
+
+	assert := assert.New(t)
+
+	assert.Nil(err)
+	assert.Equal(portalImages["ce"].Tag, LpnConfig.GetPortalImageTag("ce"))
+}
+
+func TestResetConfigKeyWithoutBuiltInValue(t *testing.T) {
+	cleanUp := newConfigWorkspace(t, "")
+	defer cleanUp()
+
+	err := SetConfigValue("images.portal.mine.image", "myorg/portal")
+	if err == nil {
+		err = SetConfigValue("images.portal.mine.tag", "latest")
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = ResetConfig("images.portal.mine")
+
+	assert := assert.New(t)
+
+	assert.Nil(err)
+	assert.NotContains(LpnConfig.GetPortalTypes(), "mine")
+}
+
+func TestResetConfig(t *testing.T) {
+	cleanUp := newConfigWorkspace(t, "")
+	defer cleanUp()
+
+	err := SetConfigValue("resources.memLimit", "4g")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = ResetConfig("")
+
+	assert := assert.New(t)
+
+	assert.Nil(err)
+
+	values, err := GetConfigValue("resources.memLimit")
+	assert.Nil(err)
+	assert.Equal("", values["resources.memLimit"])
+
+	backups, err := filepath.Glob(GetConfigFile() + ".*.bak")
+	assert.Nil(err)
+	assert.Len(backups, 1)
+}
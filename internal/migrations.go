@@ -0,0 +1,197 @@
+package internal
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// configMigration migration of the configuration file from the previous version to the next one
+type configMigration struct {
+	description string
+	migrate     func(content yaml.MapSlice) yaml.MapSlice
+}
+
+// configVersion version of the configuration file written by this version of lpn, which is the
+// number of migrations. The files without version are version 0
+const configVersion = 2
+
+// configMigrations migrations of the configuration file, in order: the version of a configuration
+// file is the number of migrations applied to it
+var configMigrations = []configMigration{
+	{"Renames images.db.postgres to images.db.postgresql, the type of the PostgreSQL datastore", renamePostgresImage},
+	{"Adds the image and tag of the built-in types missing in the configuration file", addMissingKeys},
+}
+
+// migrateConfigFile applies the pending migrations to the configuration file, backing it up first
+func migrateConfigFile() error {
+	content, err := readConfigFile()
+	if err != nil {
+		return err
+	}
+
+	version, err := getConfigVersion(content)
+	if err != nil {
+		return err
+	}
+
+	// newer versions are reported by the validation of the configuration
+	if version >= configVersion {
+		return nil
+	}
+
+	backup, err := BackupConfigFile()
+	if err != nil {
+		return err
+	}
+
+	for _, migration := range configMigrations[version:] {
+		log.WithFields(log.Fields{
+			"migration": migration.description,
+		}).Debug("Migrating the configuration file")
+
+		content = migration.migrate(content)
+	}
+
+	// the version is the first key of the file
+	content, _ = unsetConfigKey(content, []string{"version"})
+	content = append(yaml.MapSlice{{Key: "version", Value: configVersion}}, content...)
+
+	bytes, err := yaml.Marshal(content)
+	if err != nil {
+		return err
+	}
+
+	err = saveConfigBytes(bytes)
+	if err != nil {
+		return err
+	}
+
+	log.WithFields(log.Fields{
+		"backup":     backup,
+		"configFile": GetConfigFile(),
+		"from":       version,
+		"to":         configVersion,
+	}).Info("The configuration file has been migrated to the current version")
+
+	return nil
+}
+
+// addMissingKeys adds the keys the built-in types need in the configuration file, as the types
+// added to lpn after the file was written: the image and tag of the database types, which are not
+// merged when reading the file, and the ones of the missing portal types, so that they are listed
+// in it. The rest of the built-in keys are merged when reading the file
+func addMissingKeys(content yaml.MapSlice) yaml.MapSlice {
+	required := yaml.MapSlice{}
+
+	for _, t := range GetDbTypes() {
+		required = setConfigKey(required, []string{"images", "db", t}, yaml.MapSlice{
+			{Key: "image", Value: dbImages[t].Image},
+			{Key: "tag", Value: dbImages[t].Tag},
+		})
+	}
+
+	portalTypes := []string{}
+	for t := range portalImages {
+		portalTypes = append(portalTypes, t)
+	}
+
+	sort.Strings(portalTypes)
+
+	for _, t := range portalTypes {
+		if _, ok := getConfigKey(content, []string{"images", "portal", t}); ok {
+			continue
+		}
+
+		required = setConfigKey(required, []string{"images", "portal", t}, yaml.MapSlice{
+			{Key: "image", Value: portalImages[t].Image},
+			{Key: "tag", Value: portalImages[t].Tag},
+		})
+	}
+
+	return mergeMissingKeys(content, required)
+}
+
+// getConfigVersion returns the version of the configuration file, which is 0 if it is not present
+func getConfigVersion(content yaml.MapSlice) (int, error) {
+	value, ok := getConfigKey(content, []string{"version"})
+	if !ok || value == nil {
+		return 0, nil
+	}
+
+	version, err := strconv.Atoi(fmt.Sprint(value))
+	if err != nil || version < 0 {
+		return 0, fmt.Errorf("version: %v is not a version of the configuration file", value)
+	}
+
+	return version, nil
+}
+
+// getConfigKey returns the value of the key in the ordered map, matching the keys regardless of
+// their case
+func getConfigKey(mapSlice yaml.MapSlice, segments []string) (interface{}, bool) {
+	for _, item := range mapSlice {
+		if !strings.EqualFold(fmt.Sprint(item.Key), segments[0]) {
+			continue
+		}
+
+		if len(segments) == 1 {
+			return item.Value, true
+		}
+
+		child, ok := item.Value.(yaml.MapSlice)
+		if !ok {
+			return nil, false
+		}
+
+		return getConfigKey(child, segments[1:])
+	}
+
+	return nil, false
+}
+
+// mergeMissingKeys adds the keys of the defaults missing in the ordered map, recursively
+func mergeMissingKeys(mapSlice yaml.MapSlice, defaults yaml.MapSlice) yaml.MapSlice {
+	for _, item := range defaults {
+		key := fmt.Sprint(item.Key)
+
+		value, ok := getConfigKey(mapSlice, []string{key})
+		if !ok || value == nil {
+			mapSlice = setConfigKey(mapSlice, []string{key}, item.Value)
+			continue
+		}
+
+		child, isMap := value.(yaml.MapSlice)
+		defaultChild, isDefaultMap := item.Value.(yaml.MapSlice)
+		if isMap && isDefaultMap {
+			mapSlice = setConfigKey(mapSlice, []string{key}, mergeMissingKeys(child, defaultChild))
+		}
+	}
+
+	return mapSlice
+}
+
+// renamePostgresImage renames the images.db.postgres key, written by older versions of lpn, to
+// images.db.postgresql, the type of the PostgreSQL datastore. If both are present, the new one
+// is kept
+func renamePostgresImage(content yaml.MapSlice) yaml.MapSlice {
+	oldKey := []string{"images", "db", "postgres"}
+	newKey := []string{"images", "db", "postgresql"}
+
+	value, ok := getConfigKey(content, oldKey)
+	if !ok {
+		return content
+	}
+
+	content, _ = unsetConfigKey(content, oldKey)
+
+	if _, ok := getConfigKey(content, newKey); !ok {
+		content = setConfigKey(content, newKey, value)
+	}
+
+	return content
+}
@@ -0,0 +1,155 @@
+package internal
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	yaml "gopkg.in/yaml.v2"
+)
+
+func TestRenamePostgresImage(t *testing.T) {
+	content := newMapSlice(t, "images:\n  db:\n    postgres:\n      image: postgres\n      tag: 9.6\n")
+
+	content = renamePostgresImage(content)
+
+	assert := assert.New(t)
+
+	_, ok := getConfigKey(content, []string{"images", "db", "postgres"})
+	assert.False(ok)
+
+	value, ok := getConfigKey(content, []string{"images", "db", "postgresql", "tag"})
+	assert.True(ok)
+	assert.Equal(9.6, value)
+}
+
+func TestRenamePostgresImageKeepsNewKey(t *testing.T) {
+	content := newMapSlice(t,
+		"images:\n  db:\n    postgres:\n      tag: \"9.6\"\n    postgresql:\n      tag: 11-alpine\n")
+
+	content = renamePostgresImage(content)
+
+	assert := assert.New(t)
+
+	_, ok := getConfigKey(content, []string{"images", "db", "postgres"})
+	assert.False(ok)
+
+	value, ok := getConfigKey(content, []string{"images", "db", "postgresql", "tag"})
+	assert.True(ok)
+	assert.Equal("11-alpine", value)
+}
+
+func TestRenamePostgresImageWithoutOldKey(t *testing.T) {
+	content := newMapSlice(t, "images:\n  db:\n    mysql:\n      tag: \"5.7\"\n")
+
+	content = renamePostgresImage(content)
+
+	value, ok := getConfigKey(content, []string{"images", "db", "mysql", "tag"})
+
+	assert := assert.New(t)
+
+	assert.True(ok)
+	assert.Equal("5.7", value)
+}
+
+func TestMergeMissingKeys(t *testing.T) {
+	content := newMapSlice(t, "images:\n  portal:\n    ce:\n      tag: 7.2.0-ga1\n")
+	defaults := newMapSlice(t,
+		"images:\n  portal:\n    ce:\n      image: liferay/portal\n      tag: 7.0.6-ga7\n    dxp:\n      tag: 7.2.10-dxp-1\n")
+
+	content = mergeMissingKeys(content, defaults)
+
+	assert := assert.New(t)
+
+	value, _ := getConfigKey(content, []string{"images", "portal", "ce", "tag"})
+	assert.Equal("7.2.0-ga1", value)
+
+	value, _ = getConfigKey(content, []string{"images", "portal", "ce", "image"})
+	assert.Equal("liferay/portal", value)
+
+	value, _ = getConfigKey(content, []string{"images", "portal", "dxp", "tag"})
+	assert.Equal("7.2.10-dxp-1", value)
+}
+
+func TestAddMissingKeys(t *testing.T) {
+	content := newMapSlice(t, "images:\n  portal:\n    ce:\n      tag: 7.2.0-ga1\n")
+
+	content = addMissingKeys(content)
+
+	assert := assert.New(t)
+
+	// the present types are not completed, as the built-in values are merged when reading them
+	_, ok := getConfigKey(content, []string{"images", "portal", "ce", "image"})
+	assert.False(ok)
+
+	value, _ := getConfigKey(content, []string{"images", "portal", "dxp", "image"})
+	assert.Equal(portalImages["dxp"].Image, value)
+
+	value, _ = getConfigKey(content, []string{"images", "portal", "dxp", "tag"})
+	assert.Equal(portalImages["dxp"].Tag, value)
+
+	_, ok = getConfigKey(content, []string{"images", "portal", "dxp", "liferayHome"})
+	assert.False(ok)
+
+	value, _ = getConfigKey(content, []string{"images", "db", "postgresql", "image"})
+	assert.Equal(dbImages["postgresql"].Image, value)
+
+	_, ok = getConfigKey(content, []string{"resources"})
+	assert.False(ok)
+}
+
+func TestGetConfigVersion(t *testing.T) {
+	assert := assert.New(t)
+
+	version, err := getConfigVersion(newMapSlice(t, "images: {}\n"))
+	assert.Nil(err)
+	assert.Equal(0, version)
+
+	version, err = getConfigVersion(newMapSlice(t, "version: 2\n"))
+	assert.Nil(err)
+	assert.Equal(2, version)
+
+	_, err = getConfigVersion(newMapSlice(t, "version: two\n"))
+	assert.NotNil(err)
+}
+
+func TestMigrateConfigFile(t *testing.T) {
+	cleanUp := newConfigWorkspace(t, "images:\n  db:\n    postgres:\n      image: postgres\n      tag: 10-alpine\n")
+	defer cleanUp()
+
+	content, err := readConfigFile()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert := assert.New(t)
+
+	assert.Equal(yaml.MapItem{Key: "version", Value: configVersion}, content[0])
+
+	_, ok := getConfigKey(content, []string{"images", "db", "postgres"})
+	assert.False(ok)
+
+	assert.Equal("10-alpine", LpnConfig.GetDbImageTag("postgresql"))
+
+	backups, err := filepath.Glob(GetConfigFile() + ".*.bak")
+	assert.Nil(err)
+	assert.Len(backups, 1)
+}
+
+func TestMigrateConfigFileNewerVersion(t *testing.T) {
+	newer := "version: 100\nimages:\n  db:\n    postgres:\n      tag: 10-alpine\n"
+
+	cleanUp := newConfigWorkspace(t, newer)
+	defer cleanUp()
+
+	bytes, err := ioutil.ReadFile(GetConfigFile())
+
+	assert := assert.New(t)
+
+	assert.Nil(err)
+	assert.Equal(newer, string(bytes))
+
+	errs := ValidateConfig()
+	assert.NotEmpty(errs)
+}
@@ -0,0 +1,101 @@
+package internal
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// DefaultProfile profile used when none is selected, whose workspace is the workspace of lpn
+const DefaultProfile = "default"
+
+// profilesFolder folder of the workspace of lpn holding the workspaces of the named profiles
+const profilesFolder = "profiles"
+
+// profileNameRegex matches the names of the profiles, which are added to the container names
+var profileNameRegex = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// GetProfiles returns the sorted names of the profiles, which are the default one and the ones with
+// a workspace
+func GetProfiles() ([]string, error) {
+	profiles := []string{DefaultProfile}
+
+	files, err := ioutil.ReadDir(filepath.Join(getLpnHome(), profilesFolder))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	for _, f := range files {
+		if f.IsDir() && f.Name() != DefaultProfile && profileNameRegex.MatchString(f.Name()) {
+			profiles = append(profiles, f.Name())
+		}
+	}
+
+	sort.Strings(profiles[1:])
+
+	return profiles, nil
+}
+
+// GetProfileWorkspace returns the workspace of a profile, holding its configuration file, caches and
+// data directories. The workspace of the default profile is the workspace of lpn
+func GetProfileWorkspace(profile string) string {
+	if profile == "" || profile == DefaultProfile {
+		return getLpnHome()
+	}
+
+	return filepath.Join(getLpnHome(), profilesFolder, profile)
+}
+
+// UseProfile selects the profile the application works with, reading its configuration. Empty
+// selects the default profile. The workspace of a profile which does not exist is only created if
+// create is true, as when running a stack, so that a mistyped profile is not silently created
+func UseProfile(profile string, create bool) error {
+	if profile == "" {
+		profile = DefaultProfile
+	}
+
+	if !profileNameRegex.MatchString(profile) {
+		return fmt.Errorf("%s is not a valid profile name. Please use lower case letters, digits, - and _", profile)
+	}
+
+	w := GetProfileWorkspace(profile)
+
+	if _, err := os.Stat(w); os.IsNotExist(err) {
+		if !create {
+			return fmt.Errorf(
+				"the profile %s does not exist. Please check it with 'lpn profile list', or create it running a stack with it", profile)
+		}
+
+		err = os.MkdirAll(w, 0755)
+		if err != nil {
+			return err
+		}
+
+		log.WithFields(log.Fields{
+			"profile":   profile,
+			"workspace": w,
+		}).Warn("Profile created. If its name is mistyped, please remove its workspace")
+	}
+
+	LpnProfile = profile
+	LpnWorkspace = w
+
+	LpnConfig = NewConfig(w)
+
+	return nil
+}
+
+// getProfileContainerName returns the name of a container of the profile, which is the name plus
+// the profile, as lpn-ce-customer. The containers of the default profile keep their names
+func getProfileContainerName(name string) string {
+	if LpnProfile == "" || LpnProfile == DefaultProfile {
+		return name
+	}
+
+	return name + "-" + LpnProfile
+}
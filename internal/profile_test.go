@@ -0,0 +1,121 @@
+package internal
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// newTestProfile returns the name of a profile which does not exist, removing its workspace and
+// selecting the previous profile when cleaning up
+func newTestProfile(t *testing.T) (string, func()) {
+	name := "lpn-test-" + strconv.Itoa(os.Getpid())
+
+	previousProfile, previousWorkspace, previousConfig := LpnProfile, LpnWorkspace, LpnConfig
+
+	return name, func() {
+		LpnProfile, LpnWorkspace, LpnConfig = previousProfile, previousWorkspace, previousConfig
+		os.RemoveAll(GetProfileWorkspace(name))
+
+		// the folder of the profiles is kept if there are other profiles
+		os.Remove(filepath.Join(getLpnHome(), profilesFolder))
+	}
+}
+
+func TestGetProfileWorkspace(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal(getLpnHome(), GetProfileWorkspace(""))
+	assert.Equal(getLpnHome(), GetProfileWorkspace(DefaultProfile))
+	assert.Equal(filepath.Join(getLpnHome(), "profiles", "customer"), GetProfileWorkspace("customer"))
+}
+
+func TestGetProfiles(t *testing.T) {
+	name, cleanUp := newTestProfile(t)
+	defer cleanUp()
+
+	err := os.MkdirAll(GetProfileWorkspace(name), 0755)
+	if err == nil {
+		// the folders which are not valid profile names are not profiles
+		err = os.MkdirAll(filepath.Join(getLpnHome(), "profiles", "Not A Profile"), 0755)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(filepath.Join(getLpnHome(), "profiles", "Not A Profile"))
+
+	profiles, err := GetProfiles()
+
+	assert := assert.New(t)
+
+	assert.Nil(err)
+	assert.Equal(DefaultProfile, profiles[0])
+	assert.Contains(profiles, name)
+	assert.NotContains(profiles, "Not A Profile")
+}
+
+func TestUseProfileInvalidName(t *testing.T) {
+	_, cleanUp := newTestProfile(t)
+	defer cleanUp()
+
+	assert := assert.New(t)
+
+	for _, name := range []string{"Customer", "-customer", "my customer", "../customer"} {
+		err := UseProfile(name, true)
+		assert.NotNil(err, name)
+	}
+}
+
+func TestUseProfileNotCreated(t *testing.T) {
+	name, cleanUp := newTestProfile(t)
+	defer cleanUp()
+
+	err := UseProfile(name, false)
+
+	assert := assert.New(t)
+
+	assert.NotNil(err)
+
+	_, err = os.Stat(GetProfileWorkspace(name))
+	assert.True(os.IsNotExist(err))
+}
+
+func TestUseProfileCreated(t *testing.T) {
+	name, cleanUp := newTestProfile(t)
+	defer cleanUp()
+
+	err := UseProfile(name, true)
+
+	assert := assert.New(t)
+
+	assert.Nil(err)
+	assert.Equal(name, LpnProfile)
+	assert.Equal(GetProfileWorkspace(name), LpnWorkspace)
+
+	_, err = os.Stat(filepath.Join(GetProfileWorkspace(name), fileName))
+	assert.Nil(err)
+
+	// once created, the profile is used without creating it
+	err = UseProfile(name, false)
+	assert.Nil(err)
+}
+
+func TestGetProfileContainerName(t *testing.T) {
+	previousProfile := LpnProfile
+	defer func() { LpnProfile = previousProfile }()
+
+	assert := assert.New(t)
+
+	LpnProfile = ""
+	assert.Equal("lpn-ce", getProfileContainerName("lpn-ce"))
+
+	LpnProfile = DefaultProfile
+	assert.Equal("lpn-ce", getProfileContainerName("lpn-ce"))
+
+	LpnProfile = "customer"
+	assert.Equal("lpn-ce-customer", getProfileContainerName("lpn-ce"))
+	assert.Equal("db-ce-customer", getProfileContainerName("db-ce"))
+}
@@ -0,0 +1,296 @@
+package internal
+
+import (
+	"fmt"
+	"net/url"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// containerNameRegex matches the names Docker accepts for containers
+var containerNameRegex = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_.-]+$`)
+
+// envVarNameRegex matches the names of the environment variables
+var envVarNameRegex = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+// imageNameRegex matches the names of the images without tag, as liferay/portal or
+// localhost:5000/liferay/portal
+var imageNameRegex = regexp.MustCompile(
+	`^(?:[a-zA-Z0-9.-]+(?::[0-9]+)?/)?[a-z0-9]+(?:(?:[._]|__|-+)[a-z0-9]+)*(?:/[a-z0-9]+(?:(?:[._]|__|-+)[a-z0-9]+)*)*$`)
+
+// imageTypeRegex matches the names of the image types, which are used as subcommands
+var imageTypeRegex = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// memLimitRegex matches the memory limits of the containers, as 4g or 512m
+var memLimitRegex = regexp.MustCompile(`^\d+[kKmMgGtT]?[bB]?$`)
+
+// tagRegex matches the tags Docker accepts for images
+var tagRegex = regexp.MustCompile(`^[\w][\w.-]{0,127}$`)
+
+// configKey key of the schema of the configuration file. The <db> segments match the database
+// types, the <portal> segments the portal types, and the <type> segments any valid type name,
+// so that new portal types can be added
+type configKey struct {
+	pattern  string
+	validate func(value string) (interface{}, error)
+}
+
+var configSchema = []configKey{
+	{"cache.tags.ttl", validateDuration},
+	{"container.names.db.<portal>", validateContainerName},
+	{"container.names.portal.<portal>", validateContainerName},
+	{"dateTags.lookbackDays", validatePositiveInt},
+	{"images.db.<db>.image", validateImageName},
+	{"images.db.<db>.tag", validateTag},
+	{"images.portal.<type>.containerName", validateContainerName},
+	{"images.portal.<type>.dateTags", validateBool},
+	{"images.portal.<type>.debugEnvVar", validateEnvVarName},
+	{"images.portal.<type>.deployFolder", validateAbsolutePath},
+	{"images.portal.<type>.description", validateNotEmpty},
+	{"images.portal.<type>.image", validateImageName},
+	{"images.portal.<type>.liferayHome", validateAbsolutePath},
+	{"images.portal.<type>.licensed", validateBool},
+	{"images.portal.<type>.tag", validateTag},
+	{"images.portal.<type>.tagSource.password", validateString},
+	{"images.portal.<type>.tagSource.token", validateString},
+	{"images.portal.<type>.tagSource.type", validateTagSourceType},
+	{"images.portal.<type>.tagSource.url", validateURL},
+	{"images.portal.<type>.tagSource.username", validateString},
+	{"images.portal.<type>.user", validateNotEmpty},
+	{"resources.cpus", validateCPUs},
+	{"resources.dbMemLimit", validateMemLimit},
+	{"resources.memLimit", validateMemLimit},
+	{"version", validateConfigVersion},
+}
+
+// GetDbTypes returns the sorted database types of the configuration
+func GetDbTypes() []string {
+	types := []string{}
+	for t := range dbImages {
+		types = append(types, t)
+	}
+
+	sort.Strings(types)
+
+	return types
+}
+
+// ValidateConfigValue validates a value of a key of the configuration against its schema, returning
+// the key with the case of the schema, as images.portal.ce.liferayHome, and the typed value. The
+// portal types are the ones the <portal> segments of the key can take
+func ValidateConfigValue(key string, value string, portalTypes []string) (string, interface{}, error) {
+	segments := strings.Split(key, ".")
+
+	for _, schemaKey := range configSchema {
+		patternSegments := strings.Split(schemaKey.pattern, ".")
+		if len(patternSegments) != len(segments) {
+			continue
+		}
+
+		canonical, err := matchConfigKey(patternSegments, segments, portalTypes)
+		if err != nil {
+			return "", nil, err
+		}
+
+		if canonical == nil {
+			continue
+		}
+
+		typed, err := schemaKey.validate(value)
+		if err != nil {
+			return "", nil, fmt.Errorf("%s: %v", strings.Join(canonical, "."), err)
+		}
+
+		return strings.Join(canonical, "."), typed, nil
+	}
+
+	return "", nil, fmt.Errorf("%s is not a key of the configuration", key)
+}
+
+// matchConfigKey returns the segments of a key with the case of the pattern, or nil if they do not
+// match. It returns an error if they match, but a type segment is not a valid type
+func matchConfigKey(pattern []string, segments []string, portalTypes []string) ([]string, error) {
+	canonical := make([]string, len(segments))
+
+	for i, p := range pattern {
+		segment := segments[i]
+
+		switch p {
+		case "<db>", "<portal>", "<type>":
+			canonical[i] = segment
+		default:
+			if !strings.EqualFold(p, segment) {
+				return nil, nil
+			}
+
+			canonical[i] = p
+		}
+	}
+
+	for i, p := range pattern {
+		segment := segments[i]
+
+		switch p {
+		case "<db>":
+			if !contains(GetDbTypes(), segment) {
+				return nil, fmt.Errorf(
+					"%s is not a database type. Supported ones are %s", segment, strings.Join(GetDbTypes(), ", "))
+			}
+		case "<portal>":
+			if !contains(portalTypes, segment) {
+				return nil, fmt.Errorf(
+					"%s is not a portal type. Configured ones are %s", segment, strings.Join(portalTypes, ", "))
+			}
+		case "<type>":
+			if !imageTypeRegex.MatchString(segment) {
+				return nil, fmt.Errorf(
+					"%s is not a valid portal type. Please use lower case letters, digits, - and _", segment)
+			}
+		}
+	}
+
+	return canonical, nil
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
+
+func validateAbsolutePath(value string) (interface{}, error) {
+	if !path.IsAbs(value) {
+		return nil, fmt.Errorf("%s is not an absolute path of the container", value)
+	}
+
+	return value, nil
+}
+
+func validateBool(value string) (interface{}, error) {
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		return nil, fmt.Errorf("%s is not a boolean. Please use true or false", value)
+	}
+
+	return b, nil
+}
+
+func validateConfigVersion(value string) (interface{}, error) {
+	version, err := strconv.Atoi(value)
+	if err != nil || version < 0 {
+		return nil, fmt.Errorf("%s is not a version of the configuration file", value)
+	}
+
+	if version > configVersion {
+		return nil, fmt.Errorf(
+			"%d is newer than the version this lpn supports, %d. Please update lpn", version, configVersion)
+	}
+
+	return version, nil
+}
+
+func validateContainerName(value string) (interface{}, error) {
+	if !containerNameRegex.MatchString(value) {
+		return nil, fmt.Errorf("%s is not a valid container name", value)
+	}
+
+	return value, nil
+}
+
+func validateCPUs(value string) (interface{}, error) {
+	cpus, err := strconv.ParseFloat(value, 64)
+	if err != nil || cpus < 0 {
+		return nil, fmt.Errorf("%s is not a valid number of CPUs, as 1.5. Zero does not limit them", value)
+	}
+
+	return cpus, nil
+}
+
+func validateDuration(value string) (interface{}, error) {
+	_, err := time.ParseDuration(value)
+	if err != nil {
+		return nil, fmt.Errorf("%s is not a valid duration, as 1h or 30m", value)
+	}
+
+	return value, nil
+}
+
+func validateEnvVarName(value string) (interface{}, error) {
+	if !envVarNameRegex.MatchString(value) {
+		return nil, fmt.Errorf("%s is not a valid name of an environment variable", value)
+	}
+
+	return value, nil
+}
+
+func validateImageName(value string) (interface{}, error) {
+	if !imageNameRegex.MatchString(value) {
+		return nil, fmt.Errorf(
+			"%s is not a valid image name, as liferay/portal. The tag is set in its own key", value)
+	}
+
+	return value, nil
+}
+
+func validateMemLimit(value string) (interface{}, error) {
+	if value != "" && !memLimitRegex.MatchString(value) {
+		return nil, fmt.Errorf("%s is not a valid memory limit, as 4g or 512m. Empty does not limit it", value)
+	}
+
+	return value, nil
+}
+
+func validateNotEmpty(value string) (interface{}, error) {
+	if strings.TrimSpace(value) == "" {
+		return nil, fmt.Errorf("the value cannot be empty")
+	}
+
+	return value, nil
+}
+
+func validatePositiveInt(value string) (interface{}, error) {
+	i, err := strconv.Atoi(value)
+	if err != nil || i <= 0 {
+		return nil, fmt.Errorf("%s is not a positive integer", value)
+	}
+
+	return i, nil
+}
+
+func validateString(value string) (interface{}, error) {
+	return value, nil
+}
+
+func validateTag(value string) (interface{}, error) {
+	if !tagRegex.MatchString(value) {
+		return nil, fmt.Errorf("%s is not a valid image tag", value)
+	}
+
+	return value, nil
+}
+
+func validateTagSourceType(value string) (interface{}, error) {
+	if value != TagSourceHub && value != TagSourceRegistry {
+		return nil, fmt.Errorf(
+			"%s is not a tag source type. Supported ones are %s and %s", value, TagSourceHub, TagSourceRegistry)
+	}
+
+	return value, nil
+}
+
+func validateURL(value string) (interface{}, error) {
+	u, err := url.Parse(value)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("%s is not a valid URL, as https://registry.example.com", value)
+	}
+
+	return value, nil
+}
@@ -0,0 +1,140 @@
+package internal
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateConfigValue(t *testing.T) {
+	key, value, err := ValidateConfigValue("images.portal.ce.tag", "7.2.0-ga1", []string{"ce"})
+
+	assert := assert.New(t)
+
+	assert.Nil(err)
+	assert.Equal("images.portal.ce.tag", key)
+	assert.Equal("7.2.0-ga1", value)
+}
+
+func TestValidateConfigValueReturnsCaseOfSchema(t *testing.T) {
+	key, _, err := ValidateConfigValue("IMAGES.Portal.ce.LIFERAYHOME", "/opt/liferay", []string{"ce"})
+
+	assert := assert.New(t)
+
+	assert.Nil(err)
+	assert.Equal("images.portal.ce.liferayHome", key)
+}
+
+func TestValidateConfigValueTypedValues(t *testing.T) {
+	assert := assert.New(t)
+
+	_, value, err := ValidateConfigValue("resources.cpus", "1.5", []string{})
+	assert.Nil(err)
+	assert.Equal(1.5, value)
+
+	_, value, err = ValidateConfigValue("images.portal.nightly.dateTags", "true", []string{})
+	assert.Nil(err)
+	assert.Equal(true, value)
+
+	_, value, err = ValidateConfigValue("dateTags.lookbackDays", "7", []string{})
+	assert.Nil(err)
+	assert.Equal(7, value)
+}
+
+func TestValidateConfigValueInvalidValue(t *testing.T) {
+	_, _, err := ValidateConfigValue("images.portal.ce.liferayHome", "opt/liferay", []string{"ce"})
+
+	assert := assert.New(t)
+
+	assert.NotNil(err)
+	assert.True(strings.HasPrefix(err.Error(), "images.portal.ce.liferayHome: "))
+}
+
+func TestValidateConfigValueUnknownKey(t *testing.T) {
+	_, _, err := ValidateConfigValue("images.portal.ce.unknown", "value", []string{"ce"})
+
+	assert.NotNil(t, err)
+}
+
+func TestValidateConfigValueDbSegment(t *testing.T) {
+	assert := assert.New(t)
+
+	key, _, err := ValidateConfigValue("images.db.mysql.tag", "8.0", []string{})
+	assert.Nil(err)
+	assert.Equal("images.db.mysql.tag", key)
+
+	_, _, err = ValidateConfigValue("images.db.oracle.tag", "19", []string{})
+	assert.NotNil(err)
+	assert.Contains(err.Error(), "is not a database type")
+}
+
+func TestValidateConfigValuePortalSegment(t *testing.T) {
+	assert := assert.New(t)
+
+	key, _, err := ValidateConfigValue("container.names.portal.mine", "lpn-mine", []string{"ce", "mine"})
+	assert.Nil(err)
+	assert.Equal("container.names.portal.mine", key)
+
+	_, _, err = ValidateConfigValue("container.names.portal.mine", "lpn-mine", []string{"ce"})
+	assert.NotNil(err)
+	assert.Contains(err.Error(), "is not a portal type")
+}
+
+func TestValidateConfigValueTypeSegment(t *testing.T) {
+	assert := assert.New(t)
+
+	key, _, err := ValidateConfigValue("images.portal.mine.image", "myorg/portal", []string{"ce"})
+	assert.Nil(err)
+	assert.Equal("images.portal.mine.image", key)
+
+	_, _, err = ValidateConfigValue("images.portal.My.Type.image", "myorg/portal", []string{"ce"})
+	assert.NotNil(err)
+
+	_, _, err = ValidateConfigValue("images.portal.My_Type.image", "myorg/portal", []string{"ce"})
+	assert.NotNil(err)
+	assert.Contains(err.Error(), "is not a valid portal type")
+}
+
+func TestMatchConfigKey(t *testing.T) {
+	canonical, err := matchConfigKey(
+		strings.Split("images.portal.<type>.liferayHome", "."),
+		strings.Split("Images.portal.mine.liferayhome", "."), []string{})
+
+	assert := assert.New(t)
+
+	assert.Nil(err)
+	assert.Equal([]string{"images", "portal", "mine", "liferayHome"}, canonical)
+}
+
+func TestMatchConfigKeyNoMatch(t *testing.T) {
+	canonical, err := matchConfigKey(
+		strings.Split("images.portal.<type>.liferayHome", "."),
+		strings.Split("images.db.mysql.liferayHome", "."), []string{})
+
+	assert := assert.New(t)
+
+	assert.Nil(err)
+	assert.Nil(canonical)
+}
+
+func TestValidateConfigVersion(t *testing.T) {
+	assert := assert.New(t)
+
+	version, err := validateConfigVersion("1")
+	assert.Nil(err)
+	assert.Equal(1, version)
+
+	_, err = validateConfigVersion("-1")
+	assert.NotNil(err)
+
+	_, err = validateConfigVersion("100")
+	assert.NotNil(err)
+	assert.Contains(err.Error(), "Please update lpn")
+}
+
+func TestValidateConfigValueDbTagSource(t *testing.T) {
+	_, _, err := ValidateConfigValue("images.db.mysql.tagSource.type", "registry", []string{})
+
+	assert.NotNil(t, err)
+}
@@ -1,6 +1,9 @@
 package internal
 
-// LpnWorkspace where the application works
+// LpnProfile the profile the application works with
+var LpnProfile string
+
+// LpnWorkspace where the application works, which is the workspace of the profile
 var LpnWorkspace string
 
 // LpnConfig the tool's configuration, read from tool's workspace
@@ -1,53 +0,0 @@
-package liferay
-
-import internal "github.com/mdelapenya/lpn/internal"
-
-// Commerce implementation for Liferay nightly images with Commerce
-type Commerce struct {
-	Tag string
-}
-
-// GetContainerName returns the name of the container generated by this type of image
-func (c Commerce) GetContainerName() string {
-	return internal.LpnConfig.GetPortalContainerName("commerce")
-}
-
-// GetDeployFolder returns the deploy folder under Liferay Home
-func (c Commerce) GetDeployFolder() string {
-	return c.GetLiferayHome() + "/deploy"
-}
-
-// GetDockerHubTagsURL returns the URL of the available tags on Docker Hub
-func (c Commerce) GetDockerHubTagsURL() string {
-	return "liferay/commerce"
-}
-
-// GetFullyQualifiedName returns the fully qualified name of the image
-func (c Commerce) GetFullyQualifiedName() string {
-	return "docker.io/" + c.GetRepository() + ":" + c.GetTag()
-}
-
-// GetLiferayHome returns the Liferay home for nightly builds with Commerce
-func (c Commerce) GetLiferayHome() string {
-	return "/opt/liferay"
-}
-
-// GetRepository returns the repository for nightly builds with Commerce
-func (c Commerce) GetRepository() string {
-	return internal.LpnConfig.GetPortalImageName("commerce")
-}
-
-// GetTag returns the tag of the image
-func (c Commerce) GetTag() string {
-	return c.Tag
-}
-
-// GetType returns the type of the image
-func (c Commerce) GetType() string {
-	return "commerce"
-}
-
-// GetUser returns the user running the main application
-func (c Commerce) GetUser() string {
-	return "liferay"
-}
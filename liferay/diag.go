@@ -0,0 +1,38 @@
+package liferay
+
+import (
+	"errors"
+	"strings"
+)
+
+// tomcatMainClass main class of the Tomcat bundled with the portal
+const tomcatMainClass = "org.apache.catalina.startup.Bootstrap"
+
+// ParseJavaPID returns the PID of the portal among the Java processes listed by "jcmd -l", which
+// is the Tomcat process, or the only Java process other than jcmd itself
+func ParseJavaPID(output string) (string, error) {
+	pids := []string{}
+
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 2 || strings.Contains(fields[1], "JCmd") {
+			continue
+		}
+
+		if fields[1] == tomcatMainClass {
+			return fields[0], nil
+		}
+
+		pids = append(pids, fields[0])
+	}
+
+	if len(pids) == 1 {
+		return pids[0], nil
+	}
+
+	if len(pids) == 0 {
+		return "", errors.New("There is no Java process running the portal")
+	}
+
+	return "", errors.New("There are several Java processes, and none of them is Tomcat: " + strings.Join(pids, ", "))
+}
@@ -0,0 +1,55 @@
+package liferay
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseJavaPID(t *testing.T) {
+	output := `1 org.apache.catalina.startup.Bootstrap start
+245 jdk.jcmd/sun.tools.jcmd.JCmd -l
+`
+
+	pid, err := ParseJavaPID(output)
+
+	assert := assert.New(t)
+
+	assert.Nil(err)
+	assert.Equal("1", pid)
+}
+
+func TestParseJavaPIDAmongSeveralProcesses(t *testing.T) {
+	output := `12 com.liferay.osgi.Launcher
+34 org.apache.catalina.startup.Bootstrap start
+245 sun.tools.jcmd.JCmd -l
+`
+
+	pid, err := ParseJavaPID(output)
+
+	assert := assert.New(t)
+
+	assert.Nil(err)
+	assert.Equal("34", pid)
+}
+
+func TestParseJavaPIDNotTomcat(t *testing.T) {
+	pid, err := ParseJavaPID("7 com.liferay.portal.Main\n245 sun.tools.jcmd.JCmd -l\n")
+
+	assert := assert.New(t)
+
+	assert.Nil(err)
+	assert.Equal("7", pid)
+}
+
+func TestParseJavaPIDNoProcess(t *testing.T) {
+	_, err := ParseJavaPID("245 sun.tools.jcmd.JCmd -l\n")
+
+	assert.NotNil(t, err)
+}
+
+func TestParseJavaPIDSeveralProcesses(t *testing.T) {
+	_, err := ParseJavaPID("7 com.liferay.A\n8 com.liferay.B\n")
+
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,63 @@
+package liferay
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+)
+
+// ParseEnvVariable validates an environment variable in the KEY=VALUE format. A variable without
+// value, as KEY, takes the value of the local environment, as Docker does
+func ParseEnvVariable(variable string) (string, error) {
+	kv := strings.SplitN(variable, "=", 2)
+
+	key := kv[0]
+	if key == "" || strings.ContainsAny(key, " \t") {
+		return "", fmt.Errorf("%s is not a valid environment variable. Please use the KEY=VALUE format", variable)
+	}
+
+	if len(kv) == 2 {
+		return variable, nil
+	}
+
+	value, ok := os.LookupEnv(key)
+	if !ok {
+		return "", fmt.Errorf("%s has no value, and it's not set in the local environment", key)
+	}
+
+	return key + "=" + value, nil
+}
+
+// ReadEnvFile reads the environment variables of a file in the format of Docker, with a KEY=VALUE
+// variable per line. Empty lines and lines starting with # are skipped
+func ReadEnvFile(path string) ([]string, error) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	variables := []string{}
+
+	for i, line := range strings.Split(string(content), "\n") {
+		line = strings.TrimLeft(strings.TrimRight(line, "\r"), " \t")
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		variable, err := ParseEnvVariable(line)
+		if err != nil {
+			return nil, fmt.Errorf("%s:%d: %v", path, i+1, err)
+		}
+
+		variables = append(variables, variable)
+	}
+
+	return variables, nil
+}
+
+// MergeEnvVariables returns the environment variables in the KEY=VALUE format, replacing the value
+// of the variables of the base with the value of the overriding ones with the same key
+func MergeEnvVariables(base []string, overrides []string) []string {
+	return mergeKeyValues(base, overrides)
+}
@@ -0,0 +1,98 @@
+package liferay
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseEnvVariable(t *testing.T) {
+	assert := assert.New(t)
+
+	variable, err := ParseEnvVariable("TZ=Europe/Madrid")
+	assert.Nil(err)
+	assert.Equal("TZ=Europe/Madrid", variable)
+
+	variable, err = ParseEnvVariable("JAVA_OPTS=-Dfoo=bar")
+	assert.Nil(err)
+	assert.Equal("JAVA_OPTS=-Dfoo=bar", variable)
+
+	variable, err = ParseEnvVariable("EMPTY=")
+	assert.Nil(err)
+	assert.Equal("EMPTY=", variable)
+}
+
+func TestParseEnvVariableFromLocalEnvironment(t *testing.T) {
+	os.Setenv("LPN_TEST_PROXY", "http://proxy:3128")
+	defer os.Unsetenv("LPN_TEST_PROXY")
+
+	assert := assert.New(t)
+
+	variable, err := ParseEnvVariable("LPN_TEST_PROXY")
+	assert.Nil(err)
+	assert.Equal("LPN_TEST_PROXY=http://proxy:3128", variable)
+
+	_, err = ParseEnvVariable("LPN_TEST_UNSET")
+	assert.NotNil(err)
+}
+
+func TestParseEnvVariableInvalid(t *testing.T) {
+	assert := assert.New(t)
+
+	for _, variable := range []string{"", "=value", "MY VAR=value"} {
+		_, err := ParseEnvVariable(variable)
+
+		assert.NotNil(err, variable)
+	}
+}
+
+func TestReadEnvFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "lpn-env")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "portal.env")
+	ioutil.WriteFile(path, []byte("# proxy\nHTTP_PROXY=http://proxy:3128\n\n  TZ=UTC\r\n"), 0644)
+
+	variables, err := ReadEnvFile(path)
+
+	assert := assert.New(t)
+
+	assert.Nil(err)
+	assert.Equal([]string{"HTTP_PROXY=http://proxy:3128", "TZ=UTC"}, variables)
+}
+
+func TestReadEnvFileInvalid(t *testing.T) {
+	dir, err := ioutil.TempDir("", "lpn-env")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "portal.env")
+	ioutil.WriteFile(path, []byte("TZ=UTC\nMY VAR=value\n"), 0644)
+
+	_, err = ReadEnvFile(path)
+
+	assert := assert.New(t)
+
+	assert.NotNil(err)
+	assert.Contains(err.Error(), "portal.env:2")
+
+	_, err = ReadEnvFile(filepath.Join(dir, "missing.env"))
+	assert.NotNil(err)
+}
+
+func TestMergeEnvVariables(t *testing.T) {
+	merged := MergeEnvVariables(
+		[]string{"LIFERAY_JVM_OPTS=-Xmx2g", "TZ=UTC"},
+		[]string{"TZ=Europe/Madrid", "HTTP_PROXY=http://proxy:3128"})
+
+	assert.Equal(t, []string{
+		"LIFERAY_JVM_OPTS=-Xmx2g", "TZ=Europe/Madrid", "HTTP_PROXY=http://proxy:3128"}, merged)
+}
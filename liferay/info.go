@@ -0,0 +1,61 @@
+package liferay
+
+import (
+	"regexp"
+	"strings"
+)
+
+var patchesRegexp = regexp.MustCompile(`(?m)^\s*Currently installed patches:\s*(.*?)\s*$`)
+var releaseInfoRegexp = regexp.MustCompile(`Starting (Liferay .+?) \((.+?) / Build (\d+) / (.+?)\)`)
+
+// ReleaseInfo release of a portal, as printed when it starts up
+type ReleaseInfo struct {
+	Build    string `json:"build,omitempty" yaml:"build,omitempty"`
+	Codename string `json:"codename,omitempty" yaml:"codename,omitempty"`
+	Date     string `json:"date,omitempty" yaml:"date,omitempty"`
+	Name     string `json:"name,omitempty" yaml:"name,omitempty"`
+}
+
+// ParsePatchLevel returns the installed patches from the output of the "patching-tool info"
+// command, or an empty string if there are none
+func ParsePatchLevel(output string) string {
+	matches := patchesRegexp.FindStringSubmatch(output)
+	if len(matches) != 2 {
+		return ""
+	}
+
+	return matches[1]
+}
+
+// ParseReleaseInfo reads the release of the portal from its startup log line, as
+// "Starting Liferay Community Edition Portal 7.2.0 CE GA1 (Mueller / Build 7200 / June 4, 2019)"
+func ParseReleaseInfo(line string) (ReleaseInfo, bool) {
+	matches := releaseInfoRegexp.FindStringSubmatch(line)
+	if len(matches) != 5 {
+		return ReleaseInfo{}, false
+	}
+
+	return ReleaseInfo{
+		Build:    matches[3],
+		Codename: matches[2],
+		Date:     matches[4],
+		Name:     matches[1],
+	}, true
+}
+
+// ParseServerInfo reads the "key: value" lines printed by Tomcat's ServerInfo class, as
+// "Server number:  9.0.17.0" or "JVM Version:    1.8.0_201-b09"
+func ParseServerInfo(output string) map[string]string {
+	serverInfo := map[string]string{}
+
+	for _, line := range strings.Split(output, "\n") {
+		kv := strings.SplitN(line, ":", 2)
+		if len(kv) != 2 || strings.TrimSpace(kv[0]) == "" {
+			continue
+		}
+
+		serverInfo[strings.TrimSpace(kv[0])] = strings.TrimSpace(kv[1])
+	}
+
+	return serverInfo
+}
@@ -0,0 +1,71 @@
+package liferay
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParsePatchLevel(t *testing.T) {
+	output := `Loading product and patch information...
+Product information:
+  * installation type: binary
+  * build number: 7210
+  * service pack version:
+    - available SP version: Not available
+    - installable SP version: Not available
+  * patching-tool version: 2.0.12
+  * time: 2019-11-04 10:21Z
+  * host: 4c1b5a3e0f2d (8 cores)
+  * plugins: no plugins detected
+
+Currently installed patches: dxp-2-7210
+
+This installation does not include data for patching.
+`
+
+	assert.Equal(t, "dxp-2-7210", ParsePatchLevel(output))
+}
+
+func TestParsePatchLevelNoPatches(t *testing.T) {
+	assert.Equal(t, "", ParsePatchLevel("Product information:\n  * build number: 7210\n"))
+}
+
+func TestParseReleaseInfo(t *testing.T) {
+	line := "2019-06-04 10:11:12.123 INFO  [main][StartupHelperUtil:72] " +
+		"Starting Liferay Community Edition Portal 7.2.0 CE GA1 (Mueller / Build 7200 / June 4, 2019)"
+
+	releaseInfo, ok := ParseReleaseInfo(line)
+
+	assert := assert.New(t)
+
+	assert.True(ok)
+	assert.Equal("7200", releaseInfo.Build)
+	assert.Equal("Mueller", releaseInfo.Codename)
+	assert.Equal("June 4, 2019", releaseInfo.Date)
+	assert.Equal("Liferay Community Edition Portal 7.2.0 CE GA1", releaseInfo.Name)
+}
+
+func TestParseReleaseInfoOtherLine(t *testing.T) {
+	_, ok := ParseReleaseInfo("Server startup in 25000 ms")
+
+	assert.False(t, ok)
+}
+
+func TestParseServerInfo(t *testing.T) {
+	output := `Server version: Apache Tomcat/9.0.17
+Server built:   Mar 13 2019 15:55:27 UTC
+Server number:  9.0.17.0
+OS Name:        Linux
+JVM Version:    1.8.0_201-b09
+`
+
+	serverInfo := ParseServerInfo(output)
+
+	assert := assert.New(t)
+
+	assert.Equal("Apache Tomcat/9.0.17", serverInfo["Server version"])
+	assert.Equal("Mar 13 2019 15:55:27 UTC", serverInfo["Server built"])
+	assert.Equal("1.8.0_201-b09", serverInfo["JVM Version"])
+	assert.Equal(5, len(serverInfo))
+}
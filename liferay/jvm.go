@@ -0,0 +1,171 @@
+package liferay
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// memorySizeRegex matches the memory sizes of the JVM options and of the container limits, as 4g,
+// 512m or 2048M
+var memorySizeRegex = regexp.MustCompile(`^(\d+)([kKmMgGtT]?)[bB]?$`)
+
+// maxHeapRegex matches the options setting the maximum heap of the JVM
+var maxHeapRegex = regexp.MustCompile(`(?:^|\s)(?:-Xmx|-XX:MaxHeapSize=)(\S+)`)
+
+// jvmSizeRegex matches the sizes of the structured JVM options, which need a unit to avoid typos
+// as -Xmx2, which sets a heap of 2 bytes
+var jvmSizeRegex = regexp.MustCompile(`^\d+[kKmMgG]$`)
+
+// gcOptionRegex matches the options selecting a garbage collector, as -XX:+UseParallelGC
+var gcOptionRegex = regexp.MustCompile(`^-XX:\+Use\w+GC$`)
+
+// gcOptions options of the supported garbage collectors
+var gcOptions = map[string]string{
+	"g1":       "-XX:+UseG1GC",
+	"parallel": "-XX:+UseParallelGC",
+}
+
+// minHeap minimum heap the portal needs to start up
+const minHeap = 512 << 20
+
+// JVMOptions structured JVM options of the portal, validated before they are translated into
+// command line options
+type JVMOptions struct {
+	// GC garbage collector, g1 or parallel
+	GC string
+	// Heap maximum heap, as 4g
+	Heap string
+	// Metaspace maximum metaspace, as 512m
+	Metaspace string
+}
+
+// Build returns the command line options of the structured options, as "-Xmx4g -XX:+UseG1GC", or
+// an error if any of them is not valid
+func (o JVMOptions) Build() (string, error) {
+	options := []string{}
+
+	if o.Heap != "" {
+		heap, err := parseJVMSize("heap", o.Heap)
+		if err != nil {
+			return "", err
+		}
+
+		if heap < minHeap {
+			return "", fmt.Errorf("The heap %s is too small to start the portal. Please use at least 512m", o.Heap)
+		}
+
+		options = append(options, "-Xmx"+strings.ToLower(o.Heap))
+	}
+
+	if o.Metaspace != "" {
+		_, err := parseJVMSize("metaspace", o.Metaspace)
+		if err != nil {
+			return "", err
+		}
+
+		options = append(options, "-XX:MaxMetaspaceSize="+strings.ToLower(o.Metaspace))
+	}
+
+	if o.GC != "" {
+		gcOption, ok := gcOptions[strings.ToLower(o.GC)]
+		if !ok {
+			return "", fmt.Errorf("%s is not a supported garbage collector. Supported ones are g1 and parallel", o.GC)
+		}
+
+		options = append(options, gcOption)
+	}
+
+	return strings.Join(options, " "), nil
+}
+
+// AppendJVMOptions appends options to the base ones, as the defaults of an image, so that they
+// take precedence. The base options of the kinds the options set, as the maximum heap, the maximum
+// metaspace or the garbage collector, are replaced instead of duplicated, so appending the options
+// again, as when restarting a container, does not grow them. Besides, the JVM does not start with
+// several garbage collectors
+func AppendJVMOptions(base string, options string) string {
+	kinds := map[string]bool{}
+	for _, option := range strings.Fields(options) {
+		if kind := getJVMOptionKind(option); kind != "" {
+			kinds[kind] = true
+		}
+	}
+
+	result := []string{}
+	for _, option := range strings.Fields(base) {
+		if kinds[getJVMOptionKind(option)] {
+			continue
+		}
+
+		result = append(result, option)
+	}
+
+	return strings.Join(append(result, strings.Fields(options)...), " ")
+}
+
+// getJVMOptionKind returns the kind of the options replacing each other, as -Xmx4g and -Xmx2g, or
+// empty if the option can be repeated
+func getJVMOptionKind(option string) string {
+	switch {
+	case strings.HasPrefix(option, "-Xmx") || strings.HasPrefix(option, "-XX:MaxHeapSize="):
+		return "heap"
+	case strings.HasPrefix(option, "-XX:MaxMetaspaceSize="):
+		return "metaspace"
+	case gcOptionRegex.MatchString(option):
+		return "gc"
+	}
+
+	return ""
+}
+
+// ParseMemorySize returns the number of bytes of a memory size, as 4g or 512m
+func ParseMemorySize(size string) (int64, error) {
+	match := memorySizeRegex.FindStringSubmatch(strings.TrimSpace(size))
+	if match == nil {
+		return 0, fmt.Errorf("%s is not a valid memory size. Please use a number and a unit, as 4g or 512m", size)
+	}
+
+	bytes, err := strconv.ParseInt(match[1], 10, 64)
+	if err != nil {
+		return 0, err
+	}
+
+	switch strings.ToLower(match[2]) {
+	case "k":
+		bytes <<= 10
+	case "m":
+		bytes <<= 20
+	case "g":
+		bytes <<= 30
+	case "t":
+		bytes <<= 40
+	}
+
+	return bytes, nil
+}
+
+// GetMaxHeap returns the maximum heap in bytes set by the JVM options, as -Xmx4g, returning false
+// if the options do not set it. The last option wins, as in the JVM
+func GetMaxHeap(jvmOptions string) (int64, bool) {
+	matches := maxHeapRegex.FindAllStringSubmatch(jvmOptions, -1)
+	if len(matches) == 0 {
+		return 0, false
+	}
+
+	heap, err := ParseMemorySize(matches[len(matches)-1][1])
+	if err != nil {
+		return 0, false
+	}
+
+	return heap, true
+}
+
+func parseJVMSize(name string, size string) (int64, error) {
+	if !jvmSizeRegex.MatchString(size) {
+		return 0, fmt.Errorf("%s is not a valid %s. Please use a number and a unit, as 4g or 512m", size, name)
+	}
+
+	return ParseMemorySize(size)
+}
@@ -0,0 +1,123 @@
+package liferay
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseMemorySize(t *testing.T) {
+	assert := assert.New(t)
+
+	sizes := map[string]int64{
+		"1024":  1024,
+		"512k":  512 * 1024,
+		"512m":  512 * 1024 * 1024,
+		"2048M": 2048 * 1024 * 1024,
+		"4g":    4 * 1024 * 1024 * 1024,
+		"4gb":   4 * 1024 * 1024 * 1024,
+		"1t":    1024 * 1024 * 1024 * 1024,
+	}
+
+	for size, expected := range sizes {
+		bytes, err := ParseMemorySize(size)
+
+		assert.Nil(err, size)
+		assert.Equal(expected, bytes, size)
+	}
+}
+
+func TestParseMemorySizeInvalid(t *testing.T) {
+	assert := assert.New(t)
+
+	for _, size := range []string{"", "g", "1.5g", "4x", "-1g"} {
+		_, err := ParseMemorySize(size)
+
+		assert.NotNil(err, size)
+	}
+}
+
+func TestGetMaxHeap(t *testing.T) {
+	assert := assert.New(t)
+
+	heap, ok := GetMaxHeap("-Xms1g -Xmx2048m -XX:MaxMetaspaceSize=512m")
+	assert.True(ok)
+	assert.Equal(int64(2048*1024*1024), heap)
+
+	heap, ok = GetMaxHeap("-Xmx1g -XX:MaxHeapSize=4g")
+	assert.True(ok)
+	assert.Equal(int64(4*1024*1024*1024), heap)
+}
+
+func TestGetMaxHeapNotSet(t *testing.T) {
+	assert := assert.New(t)
+
+	_, ok := GetMaxHeap("-Xms1g -XX:+UseG1GC")
+	assert.False(ok)
+
+	_, ok = GetMaxHeap("-Xmxfoo")
+	assert.False(ok)
+}
+
+func TestJVMOptionsBuild(t *testing.T) {
+	assert := assert.New(t)
+
+	options, err := JVMOptions{GC: "G1", Heap: "4G", Metaspace: "512m"}.Build()
+	assert.Nil(err)
+	assert.Equal("-Xmx4g -XX:MaxMetaspaceSize=512m -XX:+UseG1GC", options)
+
+	options, err = JVMOptions{}.Build()
+	assert.Nil(err)
+	assert.Equal("", options)
+}
+
+func TestJVMOptionsBuildInvalid(t *testing.T) {
+	assert := assert.New(t)
+
+	invalid := []JVMOptions{
+		{Heap: "2"},
+		{Heap: "256m"},
+		{Heap: "-Xmx4g"},
+		{Metaspace: "512"},
+		{GC: "cms"},
+	}
+
+	for _, options := range invalid {
+		_, err := options.Build()
+
+		assert.NotNil(err, options)
+	}
+}
+
+func TestAppendJVMOptions(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal(
+		"-Dfile.encoding=UTF8 -Xmx4g",
+		AppendJVMOptions("-Dfile.encoding=UTF8 -Xmx2048m", "-Xmx4g"))
+	assert.Equal(
+		"-Xms1g -Xmx4g -XX:MaxMetaspaceSize=512m",
+		AppendJVMOptions("-Xms1g -XX:MaxHeapSize=2g -XX:MaxMetaspaceSize=256m", "-Xmx4g -XX:MaxMetaspaceSize=512m"))
+	assert.Equal(
+		"-Xmx2048m -XX:+UseG1GC",
+		AppendJVMOptions("-XX:+UseParallelGC -Xmx2048m", "-XX:+UseG1GC"))
+	assert.Equal("-Xmx4g", AppendJVMOptions("", "-Xmx4g"))
+	assert.Equal("-Xmx2048m", AppendJVMOptions("-Xmx2048m", ""))
+}
+
+func TestAppendJVMOptionsRestartingTwice(t *testing.T) {
+	image := "-Dfile.encoding=UTF8 -Xms2g -Xmx2g -XX:+UseParallelGC"
+
+	// the options of a restart are appended to the ones the container was run with
+	first := AppendJVMOptions(image, "-Xmx4g -XX:MaxMetaspaceSize=512m -XX:+UseG1GC")
+	second := AppendJVMOptions(first, "-Xmx4g -XX:MaxMetaspaceSize=512m -XX:+UseG1GC")
+
+	assert := assert.New(t)
+
+	assert.Equal("-Dfile.encoding=UTF8 -Xms2g -Xmx4g -XX:MaxMetaspaceSize=512m -XX:+UseG1GC", first)
+	assert.Equal(first, second)
+
+	third := AppendJVMOptions(second, "-Xmx6g -XX:+UseParallelGC")
+
+	assert.Equal("-Dfile.encoding=UTF8 -Xms2g -XX:MaxMetaspaceSize=512m -Xmx6g -XX:+UseParallelGC", third)
+}
@@ -0,0 +1,82 @@
+package liferay
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"strings"
+	"time"
+)
+
+// licenseDateLayouts layouts of the dates of the activation keys
+var licenseDateLayouts = []string{
+	"Monday, January 2, 2006",
+	"January 2, 2006",
+	"2006-01-02",
+}
+
+// License activation key of a Liferay DXP image, read from its XML file
+type License struct {
+	AccountName    string `xml:"account-name" json:"accountName,omitempty" yaml:"accountName,omitempty"`
+	ExpirationDate string `xml:"expiration-date" json:"expirationDate" yaml:"expirationDate"`
+	LicenseType    string `xml:"license-type" json:"licenseType" yaml:"licenseType"`
+	Owner          string `xml:"owner" json:"owner" yaml:"owner"`
+	ProductName    string `xml:"product-name" json:"productName,omitempty" yaml:"productName,omitempty"`
+	ProductVersion string `xml:"product-version" json:"productVersion,omitempty" yaml:"productVersion,omitempty"`
+	StartDate      string `xml:"start-date" json:"startDate,omitempty" yaml:"startDate,omitempty"`
+}
+
+// ParseLicense reads an activation key from the content of its XML file
+func ParseLicense(content []byte) (License, error) {
+	var license License
+
+	err := xml.Unmarshal(content, &struct {
+		XMLName xml.Name `xml:"license"`
+		*License
+	}{License: &license})
+	if err != nil {
+		return License{}, fmt.Errorf("The activation key is not valid: %v", err)
+	}
+
+	if license.ExpirationDate == "" {
+		return License{}, fmt.Errorf("The activation key does not have an expiration date")
+	}
+
+	return license, nil
+}
+
+// ReadLicense reads an activation key from its XML file
+func ReadLicense(path string) (License, error) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return License{}, err
+	}
+
+	return ParseLicense(content)
+}
+
+// GetDaysToExpire returns the number of days from a moment until the expiration date of the key,
+// which is negative if the key has already expired
+func (l License) GetDaysToExpire(from time.Time) (int, error) {
+	expiration, err := l.GetExpiration()
+	if err != nil {
+		return 0, err
+	}
+
+	return int(math.Floor(expiration.Sub(from).Hours() / 24)), nil
+}
+
+// GetExpiration returns the expiration date of the key. The key is valid during the whole day
+func (l License) GetExpiration() (time.Time, error) {
+	date := strings.TrimSpace(l.ExpirationDate)
+
+	for _, layout := range licenseDateLayouts {
+		expiration, err := time.ParseInLocation(layout, date, time.Local)
+		if err == nil {
+			return expiration.AddDate(0, 0, 1), nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("%s is not a valid expiration date", l.ExpirationDate)
+}
@@ -0,0 +1,88 @@
+package liferay
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const licenseXML = `<?xml version="1.0"?>
+
+<license>
+	<account-name>Liferay Trial</account-name>
+	<owner>John Doe</owner>
+	<description>Liferay DXP Trial</description>
+	<product-name>Digital Enterprise Development</product-name>
+	<product-version>7.2</product-version>
+	<license-name>Digital Enterprise Development</license-name>
+	<license-type>developer-cluster</license-type>
+	<license-version>6</license-version>
+	<start-date>Thursday, May 30, 2019</start-date>
+	<expiration-date>Saturday, June 29, 2019</expiration-date>
+	<max-servers>1</max-servers>
+	<key>abcdef</key>
+</license>`
+
+func TestParseLicense(t *testing.T) {
+	license, err := ParseLicense([]byte(licenseXML))
+
+	assert := assert.New(t)
+
+	assert.Nil(err)
+	assert.Equal("Liferay Trial", license.AccountName)
+	assert.Equal("Saturday, June 29, 2019", license.ExpirationDate)
+	assert.Equal("developer-cluster", license.LicenseType)
+	assert.Equal("John Doe", license.Owner)
+	assert.Equal("Digital Enterprise Development", license.ProductName)
+	assert.Equal("7.2", license.ProductVersion)
+	assert.Equal("Thursday, May 30, 2019", license.StartDate)
+}
+
+func TestParseLicenseNotALicense(t *testing.T) {
+	_, err := ParseLicense([]byte(`<?xml version="1.0"?><project><name>foo</name></project>`))
+
+	assert.NotNil(t, err)
+}
+
+func TestParseLicenseWithoutExpiration(t *testing.T) {
+	_, err := ParseLicense([]byte(`<?xml version="1.0"?><license><owner>John Doe</owner></license>`))
+
+	assert.NotNil(t, err)
+}
+
+func TestGetDaysToExpire(t *testing.T) {
+	license := License{ExpirationDate: "Saturday, June 29, 2019"}
+
+	assert := assert.New(t)
+
+	days, err := license.GetDaysToExpire(time.Date(2019, time.June, 19, 12, 0, 0, 0, time.Local))
+	assert.Nil(err)
+	assert.Equal(10, days)
+
+	// the key is valid during the whole expiration day
+	days, err = license.GetDaysToExpire(time.Date(2019, time.June, 29, 23, 0, 0, 0, time.Local))
+	assert.Nil(err)
+	assert.Equal(0, days)
+
+	days, err = license.GetDaysToExpire(time.Date(2019, time.July, 1, 12, 0, 0, 0, time.Local))
+	assert.Nil(err)
+	assert.Equal(-2, days)
+}
+
+func TestGetExpirationLayouts(t *testing.T) {
+	assert := assert.New(t)
+
+	for _, date := range []string{"Saturday, June 29, 2019", "June 29, 2019", "2019-06-29"} {
+		expiration, err := License{ExpirationDate: date}.GetExpiration()
+
+		assert.Nil(err)
+		assert.Equal(time.Date(2019, time.June, 30, 0, 0, 0, 0, time.Local), expiration)
+	}
+}
+
+func TestGetExpirationNotValid(t *testing.T) {
+	_, err := License{ExpirationDate: "tomorrow"}.GetExpiration()
+
+	assert.NotNil(t, err)
+}
@@ -1,14 +1,57 @@
 package liferay
 
+import (
+	"path"
+	"strings"
+)
+
 // Image interface defining the contract for Liferay Portal docker images
 type Image interface {
 	GetContainerName() string
-	GetFullyQualifiedName() string
+	GetDebugEnvVar() string
 	GetDeployFolder() string
+	GetDescription() string
 	GetDockerHubTagsURL() string
+	GetFullyQualifiedName() string
 	GetLiferayHome() string
 	GetRepository() string
 	GetTag() string
 	GetType() string
 	GetUser() string
+	HasDateTags() bool
+	RequiresLicense() bool
+}
+
+// DockerHubRegistry registry host of the images without one, which are Docker Hub's
+const DockerHubRegistry = "docker.io"
+
+// SplitRepository splits an image repository into the registry host and the repository name. The
+// first part of the repository is a host if it contains a dot or a port, or it's localhost, as
+// private registries do. Otherwise, the host is Docker Hub's
+func SplitRepository(repository string) (string, string) {
+	parts := strings.SplitN(repository, "/", 2)
+
+	if len(parts) == 2 && (strings.ContainsAny(parts[0], ".:") || parts[0] == "localhost") {
+		return parts[0], parts[1]
+	}
+
+	return DockerHubRegistry, repository
+}
+
+// getFullyQualifiedName returns the fully qualified name of an image, prefixed by Docker Hub's
+// registry unless the repository already contains a registry host, as private registries do
+func getFullyQualifiedName(repository string, tag string) string {
+	host, name := SplitRepository(repository)
+
+	return host + "/" + name + ":" + tag
+}
+
+// GetContainerPath returns the absolute path of a file in the container of the image, resolving
+// the relative paths against Liferay Home
+func GetContainerPath(image Image, p string) string {
+	if path.IsAbs(p) {
+		return path.Clean(p)
+	}
+
+	return path.Join(image.GetLiferayHome(), p)
 }
@@ -0,0 +1,59 @@
+package liferay
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetFullyQualifiedNameDockerHub(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal("docker.io/liferay/portal:foo", getFullyQualifiedName("liferay/portal", "foo"))
+}
+
+func TestGetFullyQualifiedNamePrivateRegistry(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal(
+		"harbor.mycorp.com/liferay/portal:foo",
+		getFullyQualifiedName("harbor.mycorp.com/liferay/portal", "foo"))
+}
+
+func TestGetFullyQualifiedNamePrivateRegistryWithPort(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal(
+		"localhost:5000/liferay/portal:foo", getFullyQualifiedName("localhost:5000/liferay/portal", "foo"))
+}
+
+func TestSplitRepository(t *testing.T) {
+	assert := assert.New(t)
+
+	host, repository := SplitRepository("liferay/portal")
+	assert.Equal("docker.io", host)
+	assert.Equal("liferay/portal", repository)
+
+	host, repository = SplitRepository("postgres")
+	assert.Equal("docker.io", host)
+	assert.Equal("postgres", repository)
+
+	host, repository = SplitRepository("harbor.mycorp.com/liferay/portal")
+	assert.Equal("harbor.mycorp.com", host)
+	assert.Equal("liferay/portal", repository)
+
+	host, repository = SplitRepository("localhost:5000/portal")
+	assert.Equal("localhost:5000", host)
+	assert.Equal("portal", repository)
+}
+
+func TestGetContainerPath(t *testing.T) {
+	image := Portal{Type: "ce"}
+
+	assert := assert.New(t)
+
+	assert.Equal("/opt/liferay/portal-ext.properties", GetContainerPath(image, "portal-ext.properties"))
+	assert.Equal("/opt/liferay/osgi/state", GetContainerPath(image, "osgi/state/"))
+	assert.Equal("/opt/liferay", GetContainerPath(image, "."))
+	assert.Equal("/tmp/dumps", GetContainerPath(image, "/tmp/dumps/"))
+}
@@ -0,0 +1,197 @@
+package liferay
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+)
+
+// logLevels levels of the portal logs, from the least to the most severe
+var logLevels = []string{"TRACE", "DEBUG", "INFO", "WARN", "ERROR", "FATAL"}
+
+// logLevelAliases levels of the Tomcat logs, and their equivalent in the portal logs
+var logLevelAliases = map[string]string{
+	"SEVERE":  "ERROR",
+	"WARNING": "WARN",
+}
+
+// logLevelColors ANSI colors of the levels
+var logLevelColors = map[string]string{
+	"TRACE": "\x1b[36m",
+	"DEBUG": "\x1b[36m",
+	"INFO":  "\x1b[32m",
+	"WARN":  "\x1b[33m",
+	"ERROR": "\x1b[31m",
+	"FATAL": "\x1b[1;31m",
+}
+
+const colorReset = "\x1b[0m"
+
+// logLineRegex matches the lines of the portal logs, as
+// "2019-11-04 10:21:00.123 INFO  [main][StartupHelperUtil:72] Starting", and of the Tomcat logs, as
+// "04-Nov-2019 10:21:00.123 INFO [main] org.apache.catalina.startup.Catalina.start Server startup".
+// The timestamps added by Docker are part of the prefix
+var logLineRegex = regexp.MustCompile(
+	`^\d[^\[]{0,63}?\s(TRACE|DEBUG|INFO|WARN|WARNING|ERROR|FATAL|SEVERE)\s+\[[^\]]*\](?:\[([^\]:]+)(?::\d+)?\]|\s+([\w.$]+))`)
+
+// LogLine a line of the logs starting a log entry
+type LogLine struct {
+	Level  string
+	Logger string
+	// levelStart and levelEnd delimit the level in the line
+	levelStart int
+	levelEnd   int
+}
+
+// ParseLogLine parses a line of the logs, returning false if it does not start a log entry, as the
+// lines of the stack traces
+func ParseLogLine(line string) (LogLine, bool) {
+	match := logLineRegex.FindStringSubmatchIndex(line)
+	if match == nil {
+		return LogLine{}, false
+	}
+
+	level := line[match[2]:match[3]]
+	if alias, ok := logLevelAliases[level]; ok {
+		level = alias
+	}
+
+	logLine := LogLine{
+		Level:      level,
+		levelStart: match[2],
+		levelEnd:   match[3],
+	}
+
+	if match[4] >= 0 {
+		logLine.Logger = line[match[4]:match[5]]
+	} else {
+		logLine.Logger = line[match[6]:match[7]]
+	}
+
+	return logLine, true
+}
+
+// ValidateLogLevel returns the level in upper case, or an error if it's not a level of the logs
+func ValidateLogLevel(level string) (string, error) {
+	level = strings.ToUpper(level)
+
+	if getLogLevelIndex(level) < 0 {
+		return "", fmt.Errorf("%s is not a valid level. Valid levels are %s", level, strings.Join(logLevels, ", "))
+	}
+
+	return level, nil
+}
+
+func getLogLevelIndex(level string) int {
+	for i, l := range logLevels {
+		if l == level {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// LogFilter writer of the logs of the portal, showing the log entries of a minimum level and
+// matching a regular expression. The lines following a log line, as stack traces, are kept with it
+type LogFilter struct {
+	// Colorize colors the levels with ANSI escape codes
+	Colorize bool
+	// Grep shows the log entries whose log line matches it, if not nil
+	Grep *regexp.Regexp
+	// Level shows the log entries of this level and above, if not empty. The lines not belonging
+	// to a log entry are hidden
+	Level string
+
+	buffer  bytes.Buffer
+	errors  map[string]int
+	inEntry bool
+	out     io.Writer
+	showing bool
+}
+
+// NewLogFilter returns a filter writing the log entries to a writer
+func NewLogFilter(out io.Writer) *LogFilter {
+	return &LogFilter{
+		errors: map[string]int{},
+		out:    out,
+	}
+}
+
+// Write filters the complete lines written, keeping the last line until it's complete
+func (f *LogFilter) Write(p []byte) (int, error) {
+	f.buffer.Write(p)
+
+	for {
+		index := bytes.IndexByte(f.buffer.Bytes(), '\n')
+		if index < 0 {
+			return len(p), nil
+		}
+
+		line := string(f.buffer.Next(index + 1))
+
+		err := f.writeLine(line)
+		if err != nil {
+			return len(p), err
+		}
+	}
+}
+
+// Flush filters the last line, even if it's not complete
+func (f *LogFilter) Flush() error {
+	if f.buffer.Len() == 0 {
+		return nil
+	}
+
+	return f.writeLine(string(f.buffer.Next(f.buffer.Len())))
+}
+
+// GetErrorsByLogger returns the number of ERROR and FATAL log entries written per logger, whether
+// they were shown or not
+func (f *LogFilter) GetErrorsByLogger() map[string]int {
+	return f.errors
+}
+
+func (f *LogFilter) writeLine(line string) error {
+	content := strings.TrimRight(line, "\r\n")
+
+	logLine, ok := ParseLogLine(content)
+	if ok {
+		f.inEntry = true
+		f.showing = f.isLevelShown(logLine.Level) && f.isGrepped(content)
+
+		if logLine.Level == "ERROR" || logLine.Level == "FATAL" {
+			f.errors[logLine.Logger]++
+		}
+
+		if f.showing && f.Colorize {
+			line = line[:logLine.levelStart] + logLevelColors[logLine.Level] +
+				line[logLine.levelStart:logLine.levelEnd] + colorReset + line[logLine.levelEnd:]
+		}
+	} else if !f.inEntry {
+		// lines before the first log entry, as the ones of the entrypoint of the image
+		f.showing = f.Level == "" && f.isGrepped(content)
+	}
+
+	if !f.showing {
+		return nil
+	}
+
+	_, err := io.WriteString(f.out, line)
+
+	return err
+}
+
+func (f *LogFilter) isGrepped(line string) bool {
+	return f.Grep == nil || f.Grep.MatchString(line)
+}
+
+func (f *LogFilter) isLevelShown(level string) bool {
+	if f.Level == "" {
+		return true
+	}
+
+	return getLogLevelIndex(level) >= getLogLevelIndex(f.Level)
+}
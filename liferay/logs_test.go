@@ -0,0 +1,144 @@
+package liferay
+
+import (
+	"bytes"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const logs = `Starting Liferay Portal
+2019-11-04 10:21:00.123 INFO  [main][StartupHelperUtil:72] Starting Liferay Portal 7.2.0 CE GA1
+2019-11-04 10:21:05.456 WARN  [main][DialectDetector:71] Liferay is configured to use Hypersonic
+2019-11-04 10:21:10.789 ERROR [http-nio-8080-exec-1][PortletServlet:105] Unable to process request
+java.lang.NullPointerException
+	at com.liferay.Foo.bar(Foo.java:10)
+2019-11-04 10:21:11.000 INFO  [main][ModuleFrameworkImpl:1234] Started
+04-Nov-2019 10:21:12.345 SEVERE [main] org.apache.catalina.startup.Catalina.start Server failed
+`
+
+func TestParseLogLine(t *testing.T) {
+	logLine, ok := ParseLogLine("2019-11-04 10:21:00.123 INFO  [main][StartupHelperUtil:72] Starting Liferay")
+
+	assert := assert.New(t)
+
+	assert.True(ok)
+	assert.Equal("INFO", logLine.Level)
+	assert.Equal("StartupHelperUtil", logLine.Logger)
+}
+
+func TestParseLogLineDockerTimestamp(t *testing.T) {
+	logLine, ok := ParseLogLine(
+		"2019-11-04T10:21:00.123456789Z 10:21:00,123 WARN  [localhost-startStop-1][DialectDetector:71] Hypersonic")
+
+	assert := assert.New(t)
+
+	assert.True(ok)
+	assert.Equal("WARN", logLine.Level)
+	assert.Equal("DialectDetector", logLine.Logger)
+}
+
+func TestParseLogLineTomcat(t *testing.T) {
+	logLine, ok := ParseLogLine(
+		"04-Nov-2019 10:21:12.345 WARNING [main] org.apache.catalina.startup.Catalina.start Slow startup")
+
+	assert := assert.New(t)
+
+	assert.True(ok)
+	assert.Equal("WARN", logLine.Level)
+	assert.Equal("org.apache.catalina.startup.Catalina.start", logLine.Logger)
+}
+
+func TestParseLogLineStackTrace(t *testing.T) {
+	_, ok := ParseLogLine("\tat com.liferay.Foo.bar(Foo.java:10)")
+
+	assert.False(t, ok)
+}
+
+func TestValidateLogLevel(t *testing.T) {
+	assert := assert.New(t)
+
+	level, err := ValidateLogLevel("warn")
+	assert.Nil(err)
+	assert.Equal("WARN", level)
+
+	_, err = ValidateLogLevel("verbose")
+	assert.NotNil(err)
+}
+
+func TestLogFilterLevel(t *testing.T) {
+	var out bytes.Buffer
+
+	filter := NewLogFilter(&out)
+	filter.Level = "WARN"
+
+	filter.Write([]byte(logs))
+
+	assert.Equal(t, `2019-11-04 10:21:05.456 WARN  [main][DialectDetector:71] Liferay is configured to use Hypersonic
+2019-11-04 10:21:10.789 ERROR [http-nio-8080-exec-1][PortletServlet:105] Unable to process request
+java.lang.NullPointerException
+	at com.liferay.Foo.bar(Foo.java:10)
+04-Nov-2019 10:21:12.345 SEVERE [main] org.apache.catalina.startup.Catalina.start Server failed
+`, out.String())
+}
+
+func TestLogFilterGrep(t *testing.T) {
+	var out bytes.Buffer
+
+	filter := NewLogFilter(&out)
+	filter.Grep = regexp.MustCompile("Start")
+
+	filter.Write([]byte(logs))
+
+	assert.Equal(t, `Starting Liferay Portal
+2019-11-04 10:21:00.123 INFO  [main][StartupHelperUtil:72] Starting Liferay Portal 7.2.0 CE GA1
+2019-11-04 10:21:11.000 INFO  [main][ModuleFrameworkImpl:1234] Started
+`, out.String())
+}
+
+func TestLogFilterColorize(t *testing.T) {
+	var out bytes.Buffer
+
+	filter := NewLogFilter(&out)
+	filter.Colorize = true
+	filter.Level = "ERROR"
+
+	filter.Write([]byte("2019-11-04 10:21:10.789 ERROR [main][PortletServlet:105] Unable\n"))
+
+	assert.Equal(t, "2019-11-04 10:21:10.789 \x1b[31mERROR\x1b[0m [main][PortletServlet:105] Unable\n", out.String())
+}
+
+func TestLogFilterPartialLines(t *testing.T) {
+	var out bytes.Buffer
+
+	filter := NewLogFilter(&out)
+
+	filter.Write([]byte("2019-11-04 10:21:00.123 INFO  [main][Start"))
+	assert.Equal(t, "", out.String())
+
+	filter.Write([]byte("upHelperUtil:72] Starting\n2019-11-04"))
+	assert.Equal(t, "2019-11-04 10:21:00.123 INFO  [main][StartupHelperUtil:72] Starting\n", out.String())
+
+	filter.Flush()
+	assert.Equal(t, "2019-11-04 10:21:00.123 INFO  [main][StartupHelperUtil:72] Starting\n2019-11-04", out.String())
+}
+
+func TestLogFilterErrorsByLogger(t *testing.T) {
+	var out bytes.Buffer
+
+	filter := NewLogFilter(&out)
+	filter.Level = "FATAL"
+
+	filter.Write([]byte(logs))
+	filter.Write([]byte("2019-11-04 10:22:10.789 ERROR [main][PortletServlet:105] Unable again\n"))
+
+	errors := filter.GetErrorsByLogger()
+
+	assert := assert.New(t)
+
+	assert.Equal("", out.String())
+	assert.Equal(2, len(errors))
+	assert.Equal(2, errors["PortletServlet"])
+	assert.Equal(1, errors["org.apache.catalina.startup.Catalina.start"])
+}
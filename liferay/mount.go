@@ -0,0 +1,55 @@
+package liferay
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// Mount bind mount of a local directory or file into the container of an image
+type Mount struct {
+	ReadOnly bool
+	// Source absolute path in the host
+	Source string
+	// Target path in the container, which is resolved against Liferay Home if it's relative
+	Target string
+}
+
+// ParseMount parses a bind mount in the host:container[:ro|rw] format, making the host path
+// absolute. Windows host paths, as C:\deploy, are supported
+func ParseMount(spec string) (Mount, error) {
+	parts := strings.Split(spec, ":")
+
+	// the drive letter of Windows paths contains the separator
+	if len(parts) > 2 && len(parts[0]) == 1 &&
+		(strings.HasPrefix(parts[1], `\`) || strings.HasPrefix(parts[1], "/")) {
+		parts = append([]string{parts[0] + ":" + parts[1]}, parts[2:]...)
+	}
+
+	if len(parts) < 2 || len(parts) > 3 || parts[0] == "" || parts[1] == "" {
+		return Mount{}, fmt.Errorf("%s is not a valid mount. Please use the host:container[:ro] format", spec)
+	}
+
+	mount := Mount{
+		Target: parts[1],
+	}
+
+	if len(parts) == 3 {
+		switch parts[2] {
+		case "ro":
+			mount.ReadOnly = true
+		case "rw":
+		default:
+			return Mount{}, fmt.Errorf("%s is not a valid mode of the mount %s. Supported modes are ro and rw", parts[2], spec)
+		}
+	}
+
+	source, err := filepath.Abs(parts[0])
+	if err != nil {
+		return Mount{}, err
+	}
+
+	mount.Source = source
+
+	return mount, nil
+}
@@ -0,0 +1,57 @@
+package liferay
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseMount(t *testing.T) {
+	assert := assert.New(t)
+
+	mount, err := ParseMount("/home/me/modules:/opt/liferay/osgi/modules")
+	assert.Nil(err)
+	assert.Equal(Mount{Source: "/home/me/modules", Target: "/opt/liferay/osgi/modules"}, mount)
+
+	mount, err = ParseMount("/home/me/portal-ext.properties:portal-ext.properties:ro")
+	assert.Nil(err)
+	assert.Equal(Mount{ReadOnly: true, Source: "/home/me/portal-ext.properties", Target: "portal-ext.properties"}, mount)
+
+	mount, err = ParseMount("/home/me/data:data:rw")
+	assert.Nil(err)
+	assert.False(mount.ReadOnly)
+}
+
+func TestParseMountRelativeSource(t *testing.T) {
+	wd, _ := os.Getwd()
+
+	mount, err := ParseMount("build/deploy:deploy")
+
+	assert := assert.New(t)
+
+	assert.Nil(err)
+	assert.Equal(filepath.Join(wd, "build", "deploy"), mount.Source)
+	assert.Equal("deploy", mount.Target)
+}
+
+func TestParseMountWindowsSource(t *testing.T) {
+	mount, err := ParseMount(`C:\modules:/opt/liferay/osgi/modules:ro`)
+
+	assert := assert.New(t)
+
+	assert.Nil(err)
+	assert.Equal("/opt/liferay/osgi/modules", mount.Target)
+	assert.True(mount.ReadOnly)
+}
+
+func TestParseMountInvalid(t *testing.T) {
+	assert := assert.New(t)
+
+	for _, spec := range []string{"", "/home/me/modules", ":/opt/liferay", "/home/me/modules:", "/a:/b:rx", "/a:/b:ro:rw", "C::/b"} {
+		_, err := ParseMount(spec)
+
+		assert.NotNil(err, spec)
+	}
+}
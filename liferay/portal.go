@@ -0,0 +1,143 @@
+package liferay
+
+import internal "github.com/mdelapenya/lpn/internal"
+
+// defaultDebugEnvVar environment variable enabling the debug mode in the official images
+const defaultDebugEnvVar = "LIFERAY_JPDA_ENABLED"
+
+// defaultLiferayHome Liferay home of the official images
+const defaultLiferayHome = "/opt/liferay"
+
+// defaultUser user running the portal in the official images
+const defaultUser = "liferay"
+
+// legacyLiferayHomes Liferay homes of the tags of the release images built before the Liferay home
+// was moved to /liferay, keyed by image. These images do not declare their Liferay home
+var legacyLiferayHomes = map[string]string{
+	"mdelapenya/liferay-portal:7-ce-ga5-tomcat-hsql":   "/usr/local/liferay-ce-portal-7.0-ga5",
+	"mdelapenya/liferay-portal:7-ce-ga4-tomcat-hsql":   "/usr/local/liferay-ce-portal-7.0-ga4",
+	"mdelapenya/liferay-portal:7-ce-ga3-tomcat-hsql":   "/usr/local/liferay-ce-portal-7.0-ga3",
+	"mdelapenya/liferay-portal:7-ce-ga2-tomcat-hsql":   "/usr/local/liferay-ce-portal-7.0-ga2",
+	"mdelapenya/liferay-portal:7-ce-ga1-tomcat-hsql":   "/usr/local/liferay-ce-portal-7.0-ga1",
+	"mdelapenya/liferay-portal:6.2-ce-ga6-tomcat-hsql": "/usr/local/liferay-portal-6.2-ce-ga1",
+	"mdelapenya/liferay-portal:6.1-ce-ga1-tomcat-hsql": "/usr/local/liferay-portal-6.1.0-ce-ga1",
+}
+
+// Portal implementation for the portal image types defined in the configuration file, under
+// the images.portal key
+type Portal struct {
+	Tag  string
+	Type string
+}
+
+// GetContainerName returns the name of the container generated by this type of image
+func (p Portal) GetContainerName() string {
+	return internal.LpnConfig.GetPortalContainerName(p.Type)
+}
+
+// GetDebugEnvVar returns the environment variable enabling the debug mode of the image, as in
+// the official images if it's not configured
+func (p Portal) GetDebugEnvVar() string {
+	if envVar := internal.LpnConfig.GetPortalImage(p.Type).DebugEnvVar; envVar != "" {
+		return envVar
+	}
+
+	return defaultDebugEnvVar
+}
+
+// GetDeployFolder returns the deploy folder detected from the image, or the configured one,
+// under Liferay Home if none of them are present
+func (p Portal) GetDeployFolder() string {
+	if settings, ok := getImageSettings(p.GetFullyQualifiedName()); ok && settings.DeployFolder != "" {
+		return settings.DeployFolder
+	}
+
+	if folder := internal.LpnConfig.GetPortalImage(p.Type).DeployFolder; folder != "" {
+		return folder
+	}
+
+	return p.GetLiferayHome() + "/deploy"
+}
+
+// GetDescription returns the human readable name of the type of image
+func (p Portal) GetDescription() string {
+	if description := internal.LpnConfig.GetPortalImage(p.Type).Description; description != "" {
+		return description
+	}
+
+	return p.Type
+}
+
+// GetDockerHubTagsURL returns the URL of the available tags on Docker Hub
+func (p Portal) GetDockerHubTagsURL() string {
+	return p.GetRepository()
+}
+
+// GetFullyQualifiedName returns the fully qualified name of the image
+func (p Portal) GetFullyQualifiedName() string {
+	return getFullyQualifiedName(p.GetRepository(), p.GetTag())
+}
+
+// GetLiferayHome returns the Liferay home detected from the image, or the configured one. The old
+// release tags, which do not declare it, get their known one instead of the built-in value of the
+// type, and the official images one is used if none of them are present
+func (p Portal) GetLiferayHome() string {
+	if settings, ok := getImageSettings(p.GetFullyQualifiedName()); ok && settings.LiferayHome != "" {
+		return settings.LiferayHome
+	}
+
+	// the built-in value of a type is a default, which does not apply to the legacy tags
+	home := internal.LpnConfig.GetPortalImage(p.Type).LiferayHome
+	if home != "" && home != internal.GetBuiltInPortalImage(p.Type).LiferayHome {
+		return home
+	}
+
+	if legacyHome, ok := legacyLiferayHomes[p.GetRepository()+":"+p.Tag]; ok {
+		return legacyHome
+	}
+
+	if home != "" {
+		return home
+	}
+
+	return defaultLiferayHome
+}
+
+// GetRepository returns the repository of the image
+func (p Portal) GetRepository() string {
+	return internal.LpnConfig.GetPortalImageName(p.Type)
+}
+
+// GetTag returns the tag of the image
+func (p Portal) GetTag() string {
+	return p.Tag
+}
+
+// GetType returns the type of the image
+func (p Portal) GetType() string {
+	return p.Type
+}
+
+// GetUser returns the user running the main application detected from the image, or the
+// configured one, as in the official images if none of them are present
+func (p Portal) GetUser() string {
+	if settings, ok := getImageSettings(p.GetFullyQualifiedName()); ok && settings.User != "" {
+		return settings.User
+	}
+
+	if user := internal.LpnConfig.GetPortalImage(p.Type).User; user != "" {
+		return user
+	}
+
+	return defaultUser
+}
+
+// HasDateTags returns if the tags of the image are dates, as nightly builds
+func (p Portal) HasDateTags() bool {
+	return internal.LpnConfig.GetPortalImage(p.Type).DateTags
+}
+
+// RequiresLicense returns if the image needs an activation key to start, as DXP images
+func (p Portal) RequiresLicense() bool {
+	return internal.LpnConfig.GetPortalImage(p.Type).Licensed
+}
@@ -0,0 +1,468 @@
+package liferay
+
+import (
+	"testing"
+
+	internal "github.com/mdelapenya/lpn/internal"
+	"github.com/stretchr/testify/assert"
+)
+
+func init() {
+	internal.CheckWorkspace()
+}
+
+func TestDeployFolderCE(t *testing.T) {
+	ce := Portal{Type: "ce"}
+
+	assert := assert.New(t)
+
+	assert.Equal(ce.GetLiferayHome()+"/deploy", ce.GetDeployFolder())
+}
+
+func TestGetContainerNameCE(t *testing.T) {
+	ce := Portal{Type: "ce"}
+
+	assert := assert.New(t)
+
+	assert.Equal("lpn-ce", ce.GetContainerName())
+}
+
+func TestGetDockerHubTagsURLCE(t *testing.T) {
+	ce := Portal{Type: "ce"}
+
+	assert := assert.New(t)
+
+	assert.Equal("liferay/portal", ce.GetDockerHubTagsURL())
+}
+
+func TestGetFullyQualifiedNameCE(t *testing.T) {
+	ce := Portal{Tag: "foo", Type: "ce"}
+
+	assert := assert.New(t)
+
+	assert.Equal("docker.io/liferay/portal:foo", ce.GetFullyQualifiedName())
+}
+
+func TestGetLiferayHomeCE(t *testing.T) {
+	ce := Portal{Type: "ce"}
+
+	assert := assert.New(t)
+
+	assert.Equal("/opt/liferay", ce.GetLiferayHome())
+}
+
+func TestGetCEsRepository(t *testing.T) {
+	ce := Portal{Type: "ce"}
+
+	assert := assert.New(t)
+	ces := ce.GetRepository()
+
+	assert.Equal("liferay/portal", ces)
+}
+
+func TestGetTypeCE(t *testing.T) {
+	ce := Portal{Type: "ce"}
+
+	assert := assert.New(t)
+
+	assert.Equal("ce", ce.GetType())
+}
+
+func TestGetUserCE(t *testing.T) {
+	ce := Portal{Type: "ce"}
+
+	assert := assert.New(t)
+
+	assert.Equal("liferay", ce.GetUser())
+}
+
+func TestDeployFolderCommerce(t *testing.T) {
+	commerce := Portal{Type: "commerce"}
+
+	assert := assert.New(t)
+
+	assert.Equal("/opt/liferay/deploy", commerce.GetDeployFolder())
+}
+
+func TestGetContainerNameCommerce(t *testing.T) {
+	commerce := Portal{Type: "commerce"}
+
+	assert := assert.New(t)
+
+	assert.Equal("lpn-commerce", commerce.GetContainerName())
+}
+
+func TestGetDockerHubTagsURLCommerce(t *testing.T) {
+	commerce := Portal{Type: "commerce"}
+
+	assert := assert.New(t)
+
+	assert.Equal("liferay/commerce", commerce.GetDockerHubTagsURL())
+}
+
+func TestGetFullyQualifiedNameCommerce(t *testing.T) {
+	commerce := Portal{Tag: "foo", Type: "commerce"}
+
+	assert := assert.New(t)
+
+	assert.Equal("docker.io/liferay/commerce:foo", commerce.GetFullyQualifiedName())
+}
+
+func TestGetLiferayHomeCommerce(t *testing.T) {
+	commerce := Portal{Type: "commerce"}
+
+	assert := assert.New(t)
+
+	assert.Equal("/opt/liferay", commerce.GetLiferayHome())
+}
+
+func TestGetCommerceRepository(t *testing.T) {
+	commerce := Portal{Type: "commerce"}
+
+	assert := assert.New(t)
+	commerceRepository := commerce.GetRepository()
+
+	assert.Equal("liferay/commerce", commerceRepository)
+}
+
+func TestGetTypeCommerce(t *testing.T) {
+	commerce := Portal{Type: "commerce"}
+
+	assert := assert.New(t)
+
+	assert.Equal("commerce", commerce.GetType())
+}
+
+func TestGetUserCommerce(t *testing.T) {
+	commerce := Portal{Type: "commerce"}
+
+	assert := assert.New(t)
+
+	assert.Equal("liferay", commerce.GetUser())
+}
+
+func TestDeployFolderDXP(t *testing.T) {
+	dxp := Portal{Type: "dxp"}
+
+	assert := assert.New(t)
+
+	assert.Equal(dxp.GetLiferayHome()+"/deploy", dxp.GetDeployFolder())
+}
+
+func TestGetContainerNameDXP(t *testing.T) {
+	dxp := Portal{Type: "dxp"}
+
+	assert := assert.New(t)
+
+	assert.Equal("lpn-dxp", dxp.GetContainerName())
+}
+
+func TestGetDockerHubTagsURLDXP(t *testing.T) {
+	dxp := Portal{Type: "dxp"}
+
+	assert := assert.New(t)
+
+	assert.Equal("liferay/dxp", dxp.GetDockerHubTagsURL())
+}
+
+func TestGetFullyQualifiedNameDXP(t *testing.T) {
+	dxp := Portal{Tag: "foo", Type: "dxp"}
+
+	assert := assert.New(t)
+
+	assert.Equal("docker.io/liferay/dxp:foo", dxp.GetFullyQualifiedName())
+}
+
+func TestGetLiferayHomeDXP(t *testing.T) {
+	dxp := Portal{Type: "dxp"}
+
+	assert := assert.New(t)
+
+	assert.Equal("/opt/liferay", dxp.GetLiferayHome())
+}
+
+func TestGetDXPsRepository(t *testing.T) {
+	dxp := Portal{Type: "dxp"}
+
+	assert := assert.New(t)
+	ces := dxp.GetRepository()
+
+	assert.Equal("liferay/dxp", ces)
+}
+
+func TestGetTypeDXP(t *testing.T) {
+	dxp := Portal{Type: "dxp"}
+
+	assert := assert.New(t)
+
+	assert.Equal("dxp", dxp.GetType())
+}
+
+func TestGetUserDXP(t *testing.T) {
+	dxp := Portal{Type: "dxp"}
+
+	assert := assert.New(t)
+
+	assert.Equal("liferay", dxp.GetUser())
+}
+
+func TestDeployFolderNightly(t *testing.T) {
+	nightly := Portal{Type: "nightly"}
+
+	assert := assert.New(t)
+
+	assert.Equal("/opt/liferay/deploy", nightly.GetDeployFolder())
+}
+
+func TestGetContainerNameNightly(t *testing.T) {
+	nightly := Portal{Type: "nightly"}
+
+	assert := assert.New(t)
+
+	assert.Equal("lpn-nightly", nightly.GetContainerName())
+}
+
+func TestGetDockerHubTagsURLNightly(t *testing.T) {
+	nightly := Portal{Type: "nightly"}
+
+	assert := assert.New(t)
+
+	assert.Equal("mdelapenya/portal-snapshot", nightly.GetDockerHubTagsURL())
+}
+
+func TestGetFullyQualifiedNameNightly(t *testing.T) {
+	nightly := Portal{Tag: "foo", Type: "nightly"}
+
+	assert := assert.New(t)
+
+	assert.Equal("docker.io/mdelapenya/portal-snapshot:foo", nightly.GetFullyQualifiedName())
+}
+
+func TestGetLiferayHomeNightly(t *testing.T) {
+	nightly := Portal{Type: "nightly"}
+
+	assert := assert.New(t)
+
+	assert.Equal("/opt/liferay", nightly.GetLiferayHome())
+}
+
+func TestGetNightliesRepository(t *testing.T) {
+	nightly := Portal{Type: "nightly"}
+
+	assert := assert.New(t)
+	nightlies := nightly.GetRepository()
+
+	assert.Equal("mdelapenya/portal-snapshot", nightlies)
+}
+
+func TestGetTypeNightly(t *testing.T) {
+	nightly := Portal{Type: "nightly"}
+
+	assert := assert.New(t)
+
+	assert.Equal("nightly", nightly.GetType())
+}
+
+func TestGetUserNightly(t *testing.T) {
+	nightly := Portal{Type: "nightly"}
+
+	assert := assert.New(t)
+
+	assert.Equal("liferay", nightly.GetUser())
+}
+
+func TestDeployFolderRelease(t *testing.T) {
+	release := Portal{Type: "release"}
+
+	assert := assert.New(t)
+
+	assert.Equal(release.GetLiferayHome()+"/deploy", release.GetDeployFolder())
+}
+
+func TestGetContainerNameRelease(t *testing.T) {
+	release := Portal{Type: "release"}
+
+	assert := assert.New(t)
+
+	assert.Equal("lpn-release", release.GetContainerName())
+}
+
+func TestGetDockerHubTagsURLRelease(t *testing.T) {
+	release := Portal{Type: "release"}
+
+	assert := assert.New(t)
+
+	assert.Equal("mdelapenya/liferay-portal", release.GetDockerHubTagsURL())
+}
+
+func TestGetFullyQualifiedNameRelease(t *testing.T) {
+	release := Portal{Tag: "foo", Type: "release"}
+
+	assert := assert.New(t)
+
+	assert.Equal("docker.io/mdelapenya/liferay-portal:foo", release.GetFullyQualifiedName())
+}
+
+func TestGetLiferayHomeReleaseLatest(t *testing.T) {
+	release := Portal{Tag: "latest", Type: "release"}
+
+	assert := assert.New(t)
+
+	assert.Equal("/liferay", release.GetLiferayHome())
+}
+
+func TestGetLiferayHomeRelease7_1M1(t *testing.T) {
+	release := Portal{Tag: "7.1-ce-m1-tomcat-hsql", Type: "release"}
+
+	assert := assert.New(t)
+
+	assert.Equal("/liferay", release.GetLiferayHome())
+}
+
+func TestGetLiferayHomeRelease7Ga5(t *testing.T) {
+	testGetLiferayHomeRelease7Ga(t, "5")
+}
+
+func TestGetLiferayHomeRelease7Ga4(t *testing.T) {
+	testGetLiferayHomeRelease7Ga(t, "4")
+}
+
+func TestGetLiferayHomeRelease7Ga3(t *testing.T) {
+	testGetLiferayHomeRelease7Ga(t, "3")
+}
+
+func TestGetLiferayHomeRelease7Ga2(t *testing.T) {
+	testGetLiferayHomeRelease7Ga(t, "2")
+}
+
+func TestGetLiferayHomeRelease7Ga1(t *testing.T) {
+	testGetLiferayHomeRelease7Ga(t, "1")
+}
+
+func TestGetLiferayHomeRelease6_2Ga6(t *testing.T) {
+	release := Portal{Tag: "6.2-ce-ga6-tomcat-hsql", Type: "release"}
+
+	assert := assert.New(t)
+
+	assert.Equal("/usr/local/liferay-portal-6.2-ce-ga1", release.GetLiferayHome())
+}
+
+func TestGetLiferayHomeRelease6_1Ga1(t *testing.T) {
+	release := Portal{Tag: "6.1-ce-ga1-tomcat-hsql", Type: "release"}
+
+	assert := assert.New(t)
+
+	assert.Equal("/usr/local/liferay-portal-6.1.0-ce-ga1", release.GetLiferayHome())
+}
+
+func TestGetLiferayHomeReleaseNoTag(t *testing.T) {
+	release := Portal{Type: "release"}
+
+	assert := assert.New(t)
+
+	assert.Equal("/liferay", release.GetLiferayHome())
+}
+
+func TestGetReleasesRepository(t *testing.T) {
+	release := Portal{Type: "release"}
+
+	assert := assert.New(t)
+	releases := release.GetRepository()
+
+	assert.Equal("mdelapenya/liferay-portal", releases)
+}
+
+func testGetLiferayHomeRelease7Ga(t *testing.T, ga string) {
+	release := Portal{Tag: "7-ce-ga" + ga + "-tomcat-hsql", Type: "release"}
+
+	assert := assert.New(t)
+
+	assert.Equal("/usr/local/liferay-ce-portal-7.0-ga"+ga, release.GetLiferayHome())
+}
+
+func TestGetTypeRelease(t *testing.T) {
+	release := Portal{Type: "release"}
+
+	assert := assert.New(t)
+
+	assert.Equal("release", release.GetType())
+}
+
+func TestGetUserRelease(t *testing.T) {
+	release := Portal{Type: "release"}
+
+	assert := assert.New(t)
+
+	assert.Equal("liferay", release.GetUser())
+}
+
+func TestGetDebugEnvVarCE(t *testing.T) {
+	ce := Portal{Type: "ce"}
+
+	assert := assert.New(t)
+
+	assert.Equal("LIFERAY_JPDA_ENABLED", ce.GetDebugEnvVar())
+}
+
+func TestGetDebugEnvVarRelease(t *testing.T) {
+	release := Portal{Type: "release"}
+
+	assert := assert.New(t)
+
+	assert.Equal("DEBUG_MODE", release.GetDebugEnvVar())
+}
+
+func TestHasDateTags(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.True(Portal{Type: "nightly"}.HasDateTags())
+	assert.False(Portal{Type: "ce"}.HasDateTags())
+}
+
+func TestRequiresLicense(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.True(Portal{Type: "dxp"}.RequiresLicense())
+	assert.False(Portal{Type: "ce"}.RequiresLicense())
+	assert.False(Portal{Type: "commerce"}.RequiresLicense())
+}
+
+func TestUserDefinedPortal(t *testing.T) {
+	internal.LpnConfig.Images.Portal["mycorp-dxp"] = internal.ImageConfig{
+		DebugEnvVar:  "JPDA",
+		DeployFolder: "/mnt/deploy",
+		Image:        "harbor.mycorp.com/liferay/dxp",
+		LiferayHome:  "/opt/mycorp",
+		User:         "mycorp",
+	}
+	defer delete(internal.LpnConfig.Images.Portal, "mycorp-dxp")
+
+	portal := Portal{Tag: "7.2.10", Type: "mycorp-dxp"}
+
+	assert := assert.New(t)
+
+	assert.Equal("lpn-mycorp-dxp", portal.GetContainerName())
+	assert.Equal("JPDA", portal.GetDebugEnvVar())
+	assert.Equal("/mnt/deploy", portal.GetDeployFolder())
+	assert.Equal("mycorp-dxp", portal.GetDescription())
+	assert.Equal("harbor.mycorp.com/liferay/dxp:7.2.10", portal.GetFullyQualifiedName())
+	assert.Equal("/opt/mycorp", portal.GetLiferayHome())
+	assert.Equal("mycorp", portal.GetUser())
+	assert.False(portal.HasDateTags())
+}
+
+func TestUserDefinedPortalDefaults(t *testing.T) {
+	internal.LpnConfig.Images.Portal["mycorp-portal"] = internal.ImageConfig{
+		Image: "mycorp/portal",
+	}
+	defer delete(internal.LpnConfig.Images.Portal, "mycorp-portal")
+
+	portal := Portal{Type: "mycorp-portal"}
+
+	assert := assert.New(t)
+
+	assert.Equal("LIFERAY_JPDA_ENABLED", portal.GetDebugEnvVar())
+	assert.Equal("/opt/liferay/deploy", portal.GetDeployFolder())
+	assert.Equal("/opt/liferay", portal.GetLiferayHome())
+	assert.Equal("liferay", portal.GetUser())
+}
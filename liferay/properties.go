@@ -0,0 +1,66 @@
+package liferay
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// GetPropertyEnvVariable converts a portal property into the environment variable the Liferay
+// images read it from, as "jdbc.default.driverClassName=org.postgresql.Driver" into
+// "LIFERAY_JDBC_PERIOD_DEFAULT_PERIOD_DRIVER_UPPERCASEC_LASS_UPPERCASEN_AME=org.postgresql.Driver"
+func GetPropertyEnvVariable(property string) (string, error) {
+	kv := strings.SplitN(property, "=", 2)
+	if len(kv) != 2 || strings.TrimSpace(kv[0]) == "" {
+		return "", fmt.Errorf("%s is not a valid property. Please use the key=value format", property)
+	}
+
+	var name strings.Builder
+	name.WriteString("LIFERAY_")
+
+	for _, r := range strings.TrimSpace(kv[0]) {
+		switch {
+		case r == '.':
+			name.WriteString("_PERIOD_")
+		case r == '_':
+			name.WriteString("_UNDERLINE_")
+		case r == '[':
+			name.WriteString("_OPEN_BRACKET_")
+		case r == ']':
+			name.WriteString("_CLOSE_BRACKET_")
+		case unicode.IsUpper(r):
+			name.WriteString("_UPPERCASE" + string(r) + "_")
+		default:
+			name.WriteString(strings.ToUpper(string(r)))
+		}
+	}
+
+	return name.String() + "=" + kv[1], nil
+}
+
+// MergeProperties returns the portal properties in the key=value format, replacing the value of the
+// properties of the base with the value of the overriding ones with the same key
+func MergeProperties(base []string, overrides []string) []string {
+	return mergeKeyValues(base, overrides)
+}
+
+// mergeKeyValues returns the key=value pairs of the base and the overriding ones, keeping the order
+// of the first appearance of each key and the value of the last one
+func mergeKeyValues(base []string, overrides []string) []string {
+	merged := []string{}
+	indexes := map[string]int{}
+
+	for _, property := range append(append([]string{}, base...), overrides...) {
+		key := strings.TrimSpace(strings.SplitN(property, "=", 2)[0])
+
+		if index, ok := indexes[key]; ok {
+			merged[index] = property
+			continue
+		}
+
+		indexes[key] = len(merged)
+		merged = append(merged, property)
+	}
+
+	return merged
+}
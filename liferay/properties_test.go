@@ -0,0 +1,51 @@
+package liferay
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetPropertyEnvVariable(t *testing.T) {
+	assert := assert.New(t)
+
+	env, err := GetPropertyEnvVariable("upgrade.database.auto.run=true")
+	assert.Nil(err)
+	assert.Equal("LIFERAY_UPGRADE_PERIOD_DATABASE_PERIOD_AUTO_PERIOD_RUN=true", env)
+}
+
+func TestGetPropertyEnvVariableUppercase(t *testing.T) {
+	assert := assert.New(t)
+
+	env, err := GetPropertyEnvVariable("jdbc.default.driverClassName=org.postgresql.Driver")
+	assert.Nil(err)
+	assert.Equal(
+		"LIFERAY_JDBC_PERIOD_DEFAULT_PERIOD_DRIVER_UPPERCASEC_LASS_UPPERCASEN_AME=org.postgresql.Driver", env)
+}
+
+func TestGetPropertyEnvVariableValueWithEquals(t *testing.T) {
+	assert := assert.New(t)
+
+	env, err := GetPropertyEnvVariable("jdbc.default.url=jdbc:mysql://db/lportal?useUnicode=true")
+	assert.Nil(err)
+	assert.Equal("LIFERAY_JDBC_PERIOD_DEFAULT_PERIOD_URL=jdbc:mysql://db/lportal?useUnicode=true", env)
+}
+
+func TestGetPropertyEnvVariableInvalid(t *testing.T) {
+	_, err := GetPropertyEnvVariable("upgrade.database.auto.run")
+
+	assert.NotNil(t, err)
+}
+
+func TestMergeProperties(t *testing.T) {
+	merged := MergeProperties(
+		[]string{"upgrade.database.auto.run=true", "setup.wizard.enabled=false"},
+		[]string{"setup.wizard.enabled=true", "company.default.locale=es_ES"})
+
+	assert.Equal(t, []string{
+		"upgrade.database.auto.run=true", "setup.wizard.enabled=true", "company.default.locale=es_ES"}, merged)
+}
+
+func TestMergePropertiesEmpty(t *testing.T) {
+	assert.Equal(t, []string{}, MergeProperties(nil, nil))
+}
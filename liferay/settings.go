@@ -0,0 +1,162 @@
+package liferay
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	internal "github.com/mdelapenya/lpn/internal"
+)
+
+// LabelDeployFolder image label overriding the detected deploy folder
+const LabelDeployFolder = "com.liferay.deploy.folder"
+
+// LabelLiferayHome image label overriding the detected Liferay home
+const LabelLiferayHome = "com.liferay.home"
+
+// LabelUser image label overriding the detected user
+const LabelUser = "com.liferay.user"
+
+// ImageSettings settings of a portal image, detected from its configuration. Empty values were
+// not detected, so the ones of the configuration file are used instead
+type ImageSettings struct {
+	DeployFolder string `json:"deployFolder,omitempty"`
+	LiferayHome  string `json:"liferayHome,omitempty"`
+	User         string `json:"user,omitempty"`
+}
+
+// imageSettingsCache detected settings, stored per image digest, and the digest each image name
+// was pointing to the last time it was inspected
+type imageSettingsCache struct {
+	Digests map[string]ImageSettings `json:"digests"`
+	Images  map[string]string        `json:"images"`
+}
+
+var loadedImageSettings *imageSettingsCache
+
+// DetectImageSettings detects the settings of a portal image from its configuration:
+//   - the Liferay home, from the com.liferay.home label, the LIFERAY_HOME env or the working dir
+//   - the deploy folder, from the com.liferay.deploy.folder label or the env variable of the
+//     auto.deploy.deploy.dir portal property
+//   - the user, from the com.liferay.user label or the user of the image
+func DetectImageSettings(
+	env []string, workingDir string, user string, labels map[string]string) ImageSettings {
+
+	variables := map[string]string{}
+	for _, variable := range env {
+		kv := strings.SplitN(variable, "=", 2)
+		if len(kv) == 2 {
+			variables[kv[0]] = kv[1]
+		}
+	}
+
+	settings := ImageSettings{}
+
+	switch {
+	case labels[LabelLiferayHome] != "":
+		settings.LiferayHome = labels[LabelLiferayHome]
+	case variables["LIFERAY_HOME"] != "":
+		settings.LiferayHome = variables["LIFERAY_HOME"]
+	case workingDir != "" && workingDir != "/":
+		settings.LiferayHome = workingDir
+	}
+
+	deployDirVariable, _ := GetPropertyEnvVariable("auto.deploy.deploy.dir=")
+	deployDirVariable = strings.TrimSuffix(deployDirVariable, "=")
+
+	switch {
+	case labels[LabelDeployFolder] != "":
+		settings.DeployFolder = labels[LabelDeployFolder]
+	case variables[deployDirVariable] != "":
+		settings.DeployFolder = variables[deployDirVariable]
+	}
+
+	switch {
+	case labels[LabelUser] != "":
+		settings.User = labels[LabelUser]
+	case user != "":
+		// the user could be in the user:group format
+		settings.User = strings.SplitN(user, ":", 2)[0]
+	}
+
+	return settings
+}
+
+// GetCachedImageSettings returns the settings detected for an image digest, if any
+func GetCachedImageSettings(digest string) (ImageSettings, bool) {
+	cache := loadImageSettingsCache(getImageSettingsCachePath())
+
+	settings, ok := cache.Digests[digest]
+
+	return settings, ok
+}
+
+// SaveImageSettings stores the settings detected for an image digest, and the digest the image
+// name is pointing to
+func SaveImageSettings(name string, digest string, settings ImageSettings) error {
+	return saveImageSettings(getImageSettingsCachePath(), name, digest, settings)
+}
+
+// getImageSettings returns the settings detected for the digest an image name was pointing to the
+// last time it was inspected
+func getImageSettings(name string) (ImageSettings, bool) {
+	cache := loadImageSettingsCache(getImageSettingsCachePath())
+
+	digest, ok := cache.Images[name]
+	if !ok {
+		return ImageSettings{}, false
+	}
+
+	settings, ok := cache.Digests[digest]
+
+	return settings, ok
+}
+
+func getImageSettingsCachePath() string {
+	return filepath.Join(internal.LpnWorkspace, "cache", "images.json")
+}
+
+// loadImageSettingsCache reads the cache file once, returning an empty cache if it cannot be read
+func loadImageSettingsCache(path string) *imageSettingsCache {
+	if loadedImageSettings != nil {
+		return loadedImageSettings
+	}
+
+	cache := &imageSettingsCache{
+		Digests: map[string]ImageSettings{},
+		Images:  map[string]string{},
+	}
+
+	content, err := ioutil.ReadFile(path)
+	if err == nil {
+		loaded := imageSettingsCache{}
+		if json.Unmarshal(content, &loaded) == nil && loaded.Digests != nil && loaded.Images != nil {
+			cache = &loaded
+		}
+	}
+
+	loadedImageSettings = cache
+
+	return cache
+}
+
+func saveImageSettings(path string, name string, digest string, settings ImageSettings) error {
+	cache := loadImageSettingsCache(path)
+
+	cache.Digests[digest] = settings
+	cache.Images[name] = digest
+
+	content, err := json.Marshal(cache)
+	if err != nil {
+		return err
+	}
+
+	err = os.MkdirAll(filepath.Dir(path), 0755)
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(path, content, 0644)
+}
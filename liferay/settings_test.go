@@ -0,0 +1,109 @@
+package liferay
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDetectImageSettingsFromEnv(t *testing.T) {
+	settings := DetectImageSettings(
+		[]string{"PATH=/usr/bin", "LIFERAY_HOME=/opt/liferay"}, "/opt/liferay/tomcat", "liferay:liferay",
+		map[string]string{})
+
+	assert := assert.New(t)
+
+	assert.Equal("", settings.DeployFolder)
+	assert.Equal("/opt/liferay", settings.LiferayHome)
+	assert.Equal("liferay", settings.User)
+}
+
+func TestDetectImageSettingsFromLabels(t *testing.T) {
+	settings := DetectImageSettings(
+		[]string{"LIFERAY_HOME=/opt/liferay"}, "/", "1000",
+		map[string]string{
+			LabelDeployFolder: "/mnt/deploy",
+			LabelLiferayHome:  "/opt/mycorp",
+			LabelUser:         "mycorp",
+		})
+
+	assert := assert.New(t)
+
+	assert.Equal("/mnt/deploy", settings.DeployFolder)
+	assert.Equal("/opt/mycorp", settings.LiferayHome)
+	assert.Equal("mycorp", settings.User)
+}
+
+func TestDetectImageSettingsFromPortalProperty(t *testing.T) {
+	settings := DetectImageSettings(
+		[]string{"LIFERAY_AUTO_PERIOD_DEPLOY_PERIOD_DEPLOY_PERIOD_DIR=/mnt/deploy"}, "", "",
+		map[string]string{})
+
+	assert := assert.New(t)
+
+	assert.Equal("/mnt/deploy", settings.DeployFolder)
+}
+
+func TestDetectImageSettingsFromWorkingDir(t *testing.T) {
+	settings := DetectImageSettings([]string{}, "/usr/local/liferay-ce-portal-7.0-ga5", "", map[string]string{})
+
+	assert := assert.New(t)
+
+	assert.Equal("/usr/local/liferay-ce-portal-7.0-ga5", settings.LiferayHome)
+	assert.Equal("", settings.User)
+}
+
+func TestDetectImageSettingsNothingDetected(t *testing.T) {
+	settings := DetectImageSettings([]string{}, "/", "", map[string]string{})
+
+	assert.Equal(t, ImageSettings{}, settings)
+}
+
+func TestSaveImageSettings(t *testing.T) {
+	dir, err := ioutil.TempDir("", "lpn-settings")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "cache", "images.json")
+
+	loadedImageSettings = nil
+	defer func() { loadedImageSettings = nil }()
+
+	assert := assert.New(t)
+
+	err = saveImageSettings(
+		path, "docker.io/liferay/portal:foo", "sha256:abc", ImageSettings{LiferayHome: "/opt/foo"})
+	assert.Nil(err)
+
+	// read the file again instead of the loaded cache
+	loadedImageSettings = nil
+	cache := loadImageSettingsCache(path)
+
+	assert.Equal("sha256:abc", cache.Images["docker.io/liferay/portal:foo"])
+	assert.Equal("/opt/foo", cache.Digests["sha256:abc"].LiferayHome)
+}
+
+func TestPortalUsesDetectedSettings(t *testing.T) {
+	loadedImageSettings = &imageSettingsCache{
+		Digests: map[string]ImageSettings{
+			"sha256:abc": {LiferayHome: "/opt/detected", User: "detected"},
+		},
+		Images: map[string]string{
+			"docker.io/mdelapenya/liferay-portal:7-ce-ga5-tomcat-hsql": "sha256:abc",
+		},
+	}
+	defer func() { loadedImageSettings = nil }()
+
+	release := Portal{Tag: "7-ce-ga5-tomcat-hsql", Type: "release"}
+
+	assert := assert.New(t)
+
+	assert.Equal("/opt/detected", release.GetLiferayHome())
+	assert.Equal("/opt/detected/deploy", release.GetDeployFolder())
+	assert.Equal("detected", release.GetUser())
+}
@@ -0,0 +1,281 @@
+package registry
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+
+	internal "github.com/mdelapenya/lpn/internal"
+	liferay "github.com/mdelapenya/lpn/liferay"
+	log "github.com/sirupsen/logrus"
+)
+
+// cachePageSize number of tags retrieved per request when filling the cache
+const cachePageSize = 100
+
+var unsafeCharsRegexp = regexp.MustCompile(`[^a-zA-Z0-9._-]+`)
+
+// cacheEntry the cached tags of a repository: its whole listing, if it was listed, and the pages
+// requested from a delegate which is able to read them one by one
+type cacheEntry struct {
+	Repository string
+	Tags       []Tag
+	UpdatedAt  time.Time
+	Pages      map[string]cachedPage `json:",omitempty"`
+}
+
+type cachedPage struct {
+	TagsPage  TagsPage
+	UpdatedAt time.Time
+}
+
+// CachedTagSource tag source which stores the tags of its delegate on disk, serving them while
+// they are not older than the TTL. A page of tags only caches that page, unless the delegate lists
+// all its tags at once. If the delegate cannot be reached, the stale tags are served
+type CachedTagSource struct {
+	Delegate TagSource
+	Path     string
+	Refresh  bool
+	TTL      time.Duration
+}
+
+// GetCachedTagSource returns the tag source configured for the image type, cached under the
+// workspace of the tool. The refresh argument bypasses the cache
+func GetCachedTagSource(image liferay.Image, refresh bool) TagSource {
+	tagSource := GetTagSource(image)
+
+	var sourceURL string
+	switch s := tagSource.(type) {
+	case DockerHub:
+		sourceURL = s.URL
+	case Registry:
+		sourceURL = s.URL
+	}
+
+	host := sourceURL
+	if u, err := url.Parse(sourceURL); err == nil && u.Host != "" {
+		host = u.Host
+	}
+
+	fileName := unsafeCharsRegexp.ReplaceAllString(host+"_"+tagSource.GetRepository(), "_") + ".json"
+
+	return CachedTagSource{
+		Delegate: tagSource,
+		Path:     filepath.Join(internal.LpnWorkspace, "cache", "tags", fileName),
+		Refresh:  refresh,
+		TTL:      internal.LpnConfig.GetTagsCacheTTL(),
+	}
+}
+
+// GetCachedTags returns the tags of the cache, even if they are expired, without reaching the
+// delegate: the whole listing, or the tags of the cached pages if it was never listed. It returns
+// an error if the tags were never cached
+func (c CachedTagSource) GetCachedTags() ([]Tag, error) {
+	entry, err := c.read()
+	if err != nil {
+		return nil, err
+	}
+
+	if !entry.UpdatedAt.IsZero() {
+		return entry.Tags, nil
+	}
+
+	keys := []string{}
+	for key := range entry.Pages {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	tags := []Tag{}
+	names := map[string]bool{}
+	for _, key := range keys {
+		for _, tag := range entry.Pages[key].TagsPage.Tags {
+			if !names[tag.Name] {
+				names[tag.Name] = true
+				tags = append(tags, tag)
+			}
+		}
+	}
+
+	return tags, nil
+}
+
+// GetRepository returns the repository of the delegate
+func (c CachedTagSource) GetRepository() string {
+	return c.Delegate.GetRepository()
+}
+
+// GetTags returns a page of tags, reading only that page from the delegate when it is not cached,
+// so that browsing a page does not list all the tags of a big repository
+func (c CachedTagSource) GetTags(page int, size int) (TagsPage, error) {
+	if _, ok := c.Delegate.(tagLister); ok {
+		// the delegate lists all its tags for any page, so the whole listing is cached
+		tags, err := c.listAllTags()
+		if err != nil {
+			return TagsPage{}, err
+		}
+
+		return getTagsPage(tags, page, size)
+	}
+
+	entry, cacheErr := c.read()
+	key := fmt.Sprintf("%d/%d", page, size)
+
+	if cacheErr == nil && !c.Refresh {
+		if c.isFresh(entry.UpdatedAt) {
+			return getTagsPage(entry.Tags, page, size)
+		}
+
+		if cached, ok := entry.Pages[key]; ok && c.isFresh(cached.UpdatedAt) {
+			log.WithFields(log.Fields{
+				"repository": c.GetRepository(),
+				"cache":      c.Path,
+				"page":       page,
+				"updatedAt":  cached.UpdatedAt,
+			}).Debug("Serving the page of tags from the cache")
+
+			return cached.TagsPage, nil
+		}
+	}
+
+	tagsPage, err := c.Delegate.GetTags(page, size)
+	if err == ErrPageNotFound {
+		return TagsPage{}, err
+	} else if err != nil {
+		// the tag source could be unreachable, rate limiting the requests or failing
+		if cached, ok := entry.Pages[key]; ok && cacheErr == nil {
+			c.warnStale(cached.UpdatedAt, err)
+
+			return cached.TagsPage, nil
+		}
+
+		if !entry.UpdatedAt.IsZero() && cacheErr == nil {
+			c.warnStale(entry.UpdatedAt, err)
+
+			return getTagsPage(entry.Tags, page, size)
+		}
+
+		return TagsPage{}, err
+	}
+
+	if entry.Pages == nil {
+		entry.Pages = map[string]cachedPage{}
+	}
+
+	entry.Repository = c.GetRepository()
+	entry.Pages[key] = cachedPage{TagsPage: tagsPage, UpdatedAt: time.Now()}
+
+	c.write(entry)
+
+	return tagsPage, nil
+}
+
+// listAllTags returns the cached tags, refreshing them from the delegate when they are expired
+func (c CachedTagSource) listAllTags() ([]Tag, error) {
+	entry, cacheErr := c.read()
+
+	if cacheErr == nil && !c.Refresh && c.isFresh(entry.UpdatedAt) {
+		log.WithFields(log.Fields{
+			"repository": c.GetRepository(),
+			"cache":      c.Path,
+			"updatedAt":  entry.UpdatedAt,
+		}).Debug("Serving tags from the cache")
+
+		return entry.Tags, nil
+	}
+
+	tags, err := GetAllTags(c.Delegate, cachePageSize)
+	if err != nil {
+		// the tag source could be unreachable, rate limiting the requests or failing
+		if cacheErr == nil && !entry.UpdatedAt.IsZero() {
+			c.warnStale(entry.UpdatedAt, err)
+
+			return entry.Tags, nil
+		}
+
+		return nil, err
+	}
+
+	// the whole listing serves any page, so the cached pages are not needed anymore
+	c.write(cacheEntry{
+		Repository: c.GetRepository(),
+		Tags:       tags,
+		UpdatedAt:  time.Now(),
+	})
+
+	return tags, nil
+}
+
+// isFresh returns whether the tags cached at the given time are not older than the TTL
+func (c CachedTagSource) isFresh(updatedAt time.Time) bool {
+	return !updatedAt.IsZero() && time.Since(updatedAt) < c.TTL
+}
+
+func (c CachedTagSource) warnStale(updatedAt time.Time, err error) {
+	log.WithFields(log.Fields{
+		"repository": c.GetRepository(),
+		"updatedAt":  updatedAt,
+		"error":      err,
+	}).Warn("The tag source could not list the tags. Serving stale tags from the cache")
+}
+
+// getTagsPage returns a page of the given tags
+func getTagsPage(tags []Tag, page int, size int) (TagsPage, error) {
+	from := (page - 1) * size
+	if page < 1 || size < 1 || (from >= len(tags) && len(tags) > 0) {
+		return TagsPage{}, ErrPageNotFound
+	}
+
+	to := from + size
+	if to > len(tags) {
+		to = len(tags)
+	}
+
+	tagsPage := TagsPage{
+		Count: len(tags),
+		Tags:  tags[from:to],
+	}
+
+	if to < len(tags) {
+		tagsPage.Next = strconv.Itoa(page + 1)
+	}
+
+	return tagsPage, nil
+}
+
+func (c CachedTagSource) read() (cacheEntry, error) {
+	var entry cacheEntry
+
+	bytes, err := ioutil.ReadFile(c.Path)
+	if err != nil {
+		return entry, err
+	}
+
+	err = json.Unmarshal(bytes, &entry)
+
+	return entry, err
+}
+
+func (c CachedTagSource) write(entry cacheEntry) {
+	bytes, err := json.Marshal(entry)
+	if err == nil {
+		err = os.MkdirAll(filepath.Dir(c.Path), 0755)
+	}
+	if err == nil {
+		err = ioutil.WriteFile(c.Path, bytes, 0644)
+	}
+
+	if err != nil {
+		log.WithFields(log.Fields{
+			"cache": c.Path,
+			"error": err,
+		}).Warn("Could not write the tags cache")
+	}
+}
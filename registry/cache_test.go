@@ -0,0 +1,216 @@
+package registry
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newCachedTagSource(t *testing.T, url string, ttl time.Duration) (CachedTagSource, func()) {
+	dir, err := ioutil.TempDir("", "lpn-cache")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cached := CachedTagSource{
+		Delegate: Registry{
+			Credentials: Credentials{Username: "admin", Password: "secret"},
+			Repository:  "liferay/portal",
+			URL:         url,
+		},
+		Path: filepath.Join(dir, "tags", "liferay_portal.json"),
+		TTL:  ttl,
+	}
+
+	return cached, func() { os.RemoveAll(dir) }
+}
+
+func TestCachedTagSourceGetTags(t *testing.T) {
+	server := newRegistryServer(t)
+	defer server.Close()
+
+	cached, cleanUp := newCachedTagSource(t, server.URL, time.Hour)
+	defer cleanUp()
+
+	assert := assert.New(t)
+
+	tagsPage, err := cached.GetTags(1, 2)
+	assert.Nil(err)
+	assert.Equal(3, tagsPage.Count)
+	assert.Equal([]string{"7.0.6-ga7", "7.1.3-ga4"}, names(tagsPage.Tags))
+	assert.Equal("2", tagsPage.Next)
+
+	_, err = os.Stat(cached.Path)
+	assert.Nil(err)
+
+	tagsPage, err = cached.GetTags(2, 2)
+	assert.Nil(err)
+	assert.Equal([]string{"7.2.0-ga1"}, names(tagsPage.Tags))
+	assert.Empty(tagsPage.Next)
+
+	_, err = cached.GetTags(3, 2)
+	assert.Equal(ErrPageNotFound, err)
+}
+
+func TestCachedTagSourceServesFreshCacheWithoutNetwork(t *testing.T) {
+	server := newRegistryServer(t)
+
+	cached, cleanUp := newCachedTagSource(t, server.URL, time.Hour)
+	defer cleanUp()
+
+	_, err := cached.GetTags(1, 10)
+	assert.Nil(t, err)
+
+	server.Close()
+
+	tags, err := GetAllTags(cached, 10)
+
+	assert := assert.New(t)
+	assert.Nil(err)
+	assert.Equal(3, len(tags))
+}
+
+func TestCachedTagSourceServesStaleCacheWhenUnreachable(t *testing.T) {
+	server := newRegistryServer(t)
+
+	cached, cleanUp := newCachedTagSource(t, server.URL, time.Nanosecond)
+	defer cleanUp()
+
+	_, err := cached.GetTags(1, 10)
+	assert.Nil(t, err)
+
+	server.Close()
+	time.Sleep(time.Millisecond)
+
+	tagsPage, err := cached.GetTags(1, 10)
+
+	assert := assert.New(t)
+	assert.Nil(err)
+	assert.Equal(3, len(tagsPage.Tags))
+}
+
+func TestCachedTagSourceServesStaleCacheWhenRateLimited(t *testing.T) {
+	server := newRegistryServer(t)
+	defer server.Close()
+
+	cached, cleanUp := newCachedTagSource(t, server.URL, time.Nanosecond)
+	defer cleanUp()
+
+	_, err := cached.GetTags(1, 10)
+	assert.Nil(t, err)
+
+	limited := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer limited.Close()
+
+	cached.Delegate = Registry{Repository: "liferay/portal", URL: limited.URL}
+	time.Sleep(time.Millisecond)
+
+	tagsPage, err := cached.GetTags(1, 10)
+
+	assert := assert.New(t)
+	assert.Nil(err)
+	assert.Equal(3, len(tagsPage.Tags))
+}
+
+func TestCachedTagSourceRefreshFailsWithoutCache(t *testing.T) {
+	server := newRegistryServer(t)
+	server.Close()
+
+	cached, cleanUp := newCachedTagSource(t, server.URL, time.Hour)
+	defer cleanUp()
+
+	cached.Refresh = true
+
+	_, err := cached.GetTags(1, 10)
+
+	assert.NotNil(t, err)
+}
+
+func TestCachedTagSourceGetCachedTags(t *testing.T) {
+	server := newRegistryServer(t)
+
+	cached, cleanUp := newCachedTagSource(t, server.URL, time.Nanosecond)
+	defer cleanUp()
+
+	_, err := cached.GetCachedTags()
+	assert.NotNil(t, err)
+
+	_, err = cached.GetTags(1, 10)
+	assert.Nil(t, err)
+
+	server.Close()
+	time.Sleep(time.Millisecond)
+
+	tags, err := cached.GetCachedTags()
+
+	assert := assert.New(t)
+	assert.Nil(err)
+	assert.Equal([]string{"7.0.6-ga7", "7.1.3-ga4", "7.2.0-ga1"}, names(tags))
+}
+
+func TestCachedTagSourceGetTagsCachesOnlyThePage(t *testing.T) {
+	requests := 0
+	server := newHubServer(t)
+	counter := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		http.Redirect(w, r, server.URL+r.URL.String(), http.StatusTemporaryRedirect)
+	}))
+	defer counter.Close()
+
+	cached, cleanUp := newCachedTagSource(t, counter.URL, time.Hour)
+	defer cleanUp()
+
+	cached.Delegate = DockerHub{Repository: "liferay/portal", URL: counter.URL}
+
+	assert := assert.New(t)
+
+	tagsPage, err := cached.GetTags(1, 2)
+	assert.Nil(err)
+	assert.Equal(3, tagsPage.Count)
+	assert.Equal([]string{"7.2.0-ga1", "7.1.3-ga4"}, names(tagsPage.Tags))
+	assert.Equal(1, requests)
+
+	server.Close()
+
+	// the cached page is served without reaching the tag source
+	tagsPage, err = cached.GetTags(1, 2)
+	assert.Nil(err)
+	assert.Equal([]string{"7.2.0-ga1", "7.1.3-ga4"}, names(tagsPage.Tags))
+	assert.Equal(1, requests)
+
+	_, err = cached.GetTags(2, 2)
+	assert.NotNil(err)
+
+	tags, err := cached.GetCachedTags()
+	assert.Nil(err)
+	assert.Equal([]string{"7.2.0-ga1", "7.1.3-ga4"}, names(tags))
+}
+
+func TestCachedTagSourceServesStalePageWhenUnreachable(t *testing.T) {
+	server := newHubServer(t)
+
+	cached, cleanUp := newCachedTagSource(t, server.URL, time.Nanosecond)
+	defer cleanUp()
+
+	cached.Delegate = DockerHub{Repository: "liferay/portal", URL: server.URL}
+
+	_, err := cached.GetTags(1, 2)
+	assert.Nil(t, err)
+
+	server.Close()
+	time.Sleep(time.Millisecond)
+
+	tagsPage, err := cached.GetTags(1, 2)
+
+	assert := assert.New(t)
+	assert.Nil(err)
+	assert.Equal(2, len(tagsPage.Tags))
+}
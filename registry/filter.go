@@ -0,0 +1,83 @@
+package registry
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+)
+
+// SortByDate sorts the tags from the most recently updated one
+const SortByDate = "date"
+
+// SortByName sorts the tags alphabetically
+const SortByName = "name"
+
+// SortBySize sorts the tags from the biggest one
+const SortBySize = "size"
+
+// TagsFilter criteria to filter and sort a list of tags. Empty criteria are not applied
+type TagsFilter struct {
+	Regexp string
+	Semver string
+	Sort   string
+}
+
+// Apply returns the tags matching the filter, sorted by the filter criteria
+func (f TagsFilter) Apply(tags []Tag) ([]Tag, error) {
+	var re *regexp.Regexp
+	var constraint Constraint
+	var err error
+
+	if f.Regexp != "" {
+		re, err = regexp.Compile(f.Regexp)
+		if err != nil {
+			return nil, fmt.Errorf("%s is not a valid regular expression: %v", f.Regexp, err)
+		}
+	}
+
+	if f.Semver != "" {
+		constraint, err = ParseConstraint(f.Semver)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	filtered := []Tag{}
+
+	for _, tag := range tags {
+		if re != nil && !re.MatchString(tag.Name) {
+			continue
+		}
+
+		if f.Semver != "" {
+			version, err := ParseVersion(tag.Name)
+			if err != nil || !constraint.Check(version) {
+				continue
+			}
+		}
+
+		filtered = append(filtered, tag)
+	}
+
+	switch f.Sort {
+	case "":
+		// keep the order of the tag source
+	case SortByName:
+		sort.SliceStable(filtered, func(i, j int) bool {
+			return filtered[i].Name < filtered[j].Name
+		})
+	case SortByDate:
+		// RFC 3339 dates can be compared as strings
+		sort.SliceStable(filtered, func(i, j int) bool {
+			return filtered[i].LastUpdated > filtered[j].LastUpdated
+		})
+	case SortBySize:
+		sort.SliceStable(filtered, func(i, j int) bool {
+			return filtered[i].Size > filtered[j].Size
+		})
+	default:
+		return nil, fmt.Errorf("%s is not a valid sort criteria. Supported values are [name|date|size]", f.Sort)
+	}
+
+	return filtered, nil
+}
@@ -0,0 +1,75 @@
+package registry
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var testTags = []Tag{
+	{Name: "7.1.3-ga4", LastUpdated: "2019-05-01T10:00:00Z", Size: 650},
+	{Name: "latest", LastUpdated: "2019-10-01T10:00:00Z", Size: 700},
+	{Name: "7.2.0-ga1", LastUpdated: "2019-06-11T10:00:00Z", Size: 720},
+	{Name: "7.2.1-ga2", LastUpdated: "2019-09-11T10:00:00Z", Size: 600},
+}
+
+func names(tags []Tag) []string {
+	result := []string{}
+	for _, tag := range tags {
+		result = append(result, tag.Name)
+	}
+
+	return result
+}
+
+func TestTagsFilterEmpty(t *testing.T) {
+	tags, err := TagsFilter{}.Apply(testTags)
+
+	assert := assert.New(t)
+	assert.Nil(err)
+	assert.Equal(names(testTags), names(tags))
+}
+
+func TestTagsFilterRegexp(t *testing.T) {
+	tags, err := TagsFilter{Regexp: `^7\.2\.`}.Apply(testTags)
+
+	assert := assert.New(t)
+	assert.Nil(err)
+	assert.Equal([]string{"7.2.0-ga1", "7.2.1-ga2"}, names(tags))
+}
+
+func TestTagsFilterSemverSortedByName(t *testing.T) {
+	tags, err := TagsFilter{Semver: "<7.2.1", Sort: SortByName}.Apply(testTags)
+
+	assert := assert.New(t)
+	assert.Nil(err)
+	assert.Equal([]string{"7.1.3-ga4", "7.2.0-ga1"}, names(tags))
+}
+
+func TestTagsFilterSortByDate(t *testing.T) {
+	tags, err := TagsFilter{Sort: SortByDate}.Apply(testTags)
+
+	assert := assert.New(t)
+	assert.Nil(err)
+	assert.Equal([]string{"latest", "7.2.1-ga2", "7.2.0-ga1", "7.1.3-ga4"}, names(tags))
+}
+
+func TestTagsFilterSortBySize(t *testing.T) {
+	tags, err := TagsFilter{Sort: SortBySize}.Apply(testTags)
+
+	assert := assert.New(t)
+	assert.Nil(err)
+	assert.Equal([]string{"7.2.0-ga1", "latest", "7.1.3-ga4", "7.2.1-ga2"}, names(tags))
+}
+
+func TestTagsFilterInvalidSort(t *testing.T) {
+	_, err := TagsFilter{Sort: "foo"}.Apply(testTags)
+
+	assert.NotNil(t, err)
+}
+
+func TestTagsFilterInvalidRegexp(t *testing.T) {
+	_, err := TagsFilter{Regexp: "("}.Apply(testTags)
+
+	assert.NotNil(t, err)
+}
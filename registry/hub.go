@@ -0,0 +1,102 @@
+package registry
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+type imageResponse struct {
+	Size         int
+	Architecture string
+	Variant      string
+	Features     string
+	OS           string
+	OSVersion    string `json:"os_version"`
+	OSFeatures   string `json:"os_features"`
+}
+
+type resultResponse struct {
+	Name        string
+	FullSize    int `json:"full_size"`
+	Images      []imageResponse
+	ID          int64
+	Repository  int64
+	Creator     int64
+	LastUpdater int64  `json:"last_updater"`
+	LastUpdated string `json:"last_updated"`
+	ImageID     string `json:"image_id"`
+	V2          bool
+}
+
+type tagsResponse struct {
+	Count    int
+	Next     string
+	Previous string
+	Results  []resultResponse
+}
+
+// DockerHub tag source reading tags from the Docker Hub API
+type DockerHub struct {
+	Credentials Credentials
+	Repository  string
+	URL         string
+}
+
+// GetRepository returns the repository on Docker Hub
+func (h DockerHub) GetRepository() string {
+	return h.Repository
+}
+
+// GetTags returns a page of tags from the Docker Hub API
+func (h DockerHub) GetTags(page int, size int) (TagsPage, error) {
+	tagsURL := fmt.Sprintf(
+		"%s/v2/repositories/%s/tags/?page_size=%d&page=%d", h.URL, h.Repository, size, page)
+
+	res, err := get(tagsURL, h.Credentials)
+	if err != nil {
+		return TagsPage{}, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode == http.StatusNotFound {
+		return TagsPage{}, ErrPageNotFound
+	}
+
+	if res.StatusCode != http.StatusOK {
+		return TagsPage{}, fmt.Errorf("Error getting response from the server: %s", res.Status)
+	}
+
+	tagsResponse := new(tagsResponse)
+	err = json.NewDecoder(res.Body).Decode(tagsResponse)
+	if err != nil {
+		return TagsPage{}, err
+	}
+
+	tagsPage := TagsPage{
+		Count: tagsResponse.Count,
+		Next:  tagsResponse.Next,
+		Tags:  []Tag{},
+	}
+
+	for _, r := range tagsResponse.Results {
+		tag := Tag{
+			Architectures: []string{},
+			LastUpdated:   r.LastUpdated,
+			Name:          r.Name,
+			Size:          r.FullSize,
+		}
+
+		for _, i := range r.Images {
+			tag.Architectures = append(tag.Architectures, i.Architecture)
+		}
+
+		if len(r.Images) > 0 {
+			tag.Size = r.Images[0].Size
+		}
+
+		tagsPage.Tags = append(tagsPage.Tags, tag)
+	}
+
+	return tagsPage, nil
+}
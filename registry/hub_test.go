@@ -0,0 +1,71 @@
+package registry
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newHubServer(t *testing.T) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v2/repositories/liferay/portal/tags/" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		if r.URL.Query().Get("page") != "1" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		fmt.Fprint(w, `{
+			"count": 3,
+			"next": "https://hub.docker.com/v2/repositories/liferay/portal/tags/?page=2&page_size=2",
+			"results": [
+				{"name": "7.2.0-ga1", "full_size": 1, "last_updated": "2019-06-11T10:00:00Z",
+					"images": [{"size": 700000000, "architecture": "amd64"}]},
+				{"name": "7.1.3-ga4", "last_updated": "2019-05-01T10:00:00Z",
+					"images": [{"size": 650000000, "architecture": "amd64"}]}
+			]
+		}`)
+	}))
+}
+
+func TestDockerHubGetTags(t *testing.T) {
+	server := newHubServer(t)
+	defer server.Close()
+
+	hub := DockerHub{Repository: "liferay/portal", URL: server.URL}
+
+	assert := assert.New(t)
+
+	tagsPage, err := hub.GetTags(1, 2)
+	assert.Nil(err)
+	assert.Equal(3, tagsPage.Count)
+	assert.NotEmpty(tagsPage.Next)
+	assert.Equal(2, len(tagsPage.Tags))
+	assert.Equal("7.2.0-ga1", tagsPage.Tags[0].Name)
+	assert.Equal(700000000, tagsPage.Tags[0].Size)
+	assert.Equal("2019-06-11T10:00:00Z", tagsPage.Tags[0].LastUpdated)
+	assert.Equal([]string{"amd64"}, tagsPage.Tags[0].Architectures)
+}
+
+func TestDockerHubGetTagsPageNotFound(t *testing.T) {
+	server := newHubServer(t)
+	defer server.Close()
+
+	hub := DockerHub{Repository: "liferay/portal", URL: server.URL}
+
+	_, err := hub.GetTags(103, 2)
+
+	assert.Equal(t, ErrPageNotFound, err)
+}
+
+func TestDockerHubGetRepository(t *testing.T) {
+	hub := DockerHub{Repository: "liferay/portal"}
+
+	assert.Equal(t, "liferay/portal", hub.GetRepository())
+}
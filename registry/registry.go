@@ -0,0 +1,264 @@
+package registry
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	internal "github.com/mdelapenya/lpn/internal"
+	liferay "github.com/mdelapenya/lpn/liferay"
+	log "github.com/sirupsen/logrus"
+)
+
+// DockerHubURL default URL of the Docker Hub API
+const DockerHubURL = "https://hub.docker.com"
+
+// DockerHubRegistryURL URL of the Docker Registry v2 API of Docker Hub
+const DockerHubRegistryURL = "https://registry-1.docker.io"
+
+// httpTimeout time a request to a tag source can take, so that a hung server does not block lpn
+const httpTimeout = 30 * time.Second
+
+// httpClient client of the requests to the tag sources
+var httpClient = &http.Client{Timeout: httpTimeout}
+
+// ErrPageNotFound the requested page of tags does not exist in the tag source
+var ErrPageNotFound = errors.New("There are no available tags for that pagination")
+
+// Credentials defines how to authenticate against a tag source
+type Credentials struct {
+	Password string
+	Token    string
+	Username string
+}
+
+// Tag simple model for an image tag
+type Tag struct {
+	Architectures []string
+	LastUpdated   string
+	Name          string
+	Size          int
+}
+
+// TagsPage a page of tags retrieved from a tag source, where Next is empty for the last page
+type TagsPage struct {
+	Count int
+	Next  string
+	Tags  []Tag
+}
+
+// tagLister interface for the tag sources able to list all their tags at once
+type tagLister interface {
+	listAllTags() ([]Tag, error)
+}
+
+// TagSource interface defining the contract for the sources of image tags
+type TagSource interface {
+	GetRepository() string
+	GetTags(page int, size int) (TagsPage, error)
+}
+
+// GetAllTags retrieves all tags from the tag source, reading pages of the given size while the
+// source informs that there is a next page
+func GetAllTags(tagSource TagSource, size int) ([]Tag, error) {
+	tags := []Tag{}
+
+	if lister, ok := tagSource.(tagLister); ok {
+		// the source lists all tags at once, so avoid listing them again for each page
+		return lister.listAllTags()
+	}
+
+	for page := 1; ; page++ {
+		tagsPage, err := tagSource.GetTags(page, size)
+		if err == ErrPageNotFound {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+
+		tags = append(tags, tagsPage.Tags...)
+
+		if tagsPage.Next == "" || len(tagsPage.Tags) == 0 {
+			break
+		}
+
+		log.WithFields(log.Fields{
+			"repository": tagSource.GetRepository(),
+			"page":       page,
+			"tags":       len(tags),
+		}).Debug("Following the next page of tags")
+	}
+
+	return tags, nil
+}
+
+// GetTagSource returns the tag source configured for the image type, which defaults to Docker Hub
+func GetTagSource(image liferay.Image) TagSource {
+	config := internal.LpnConfig.GetPortalImageTagSource(image.GetType())
+
+	credentials := Credentials{
+		Password: config.Password,
+		Token:    config.Token,
+		Username: config.Username,
+	}
+
+	if config.Type == internal.TagSourceRegistry {
+		registryURL, repository := getRegistryEndpoint(image.GetRepository(), config.URL)
+
+		return Registry{
+			Credentials: credentials,
+			Repository:  repository,
+			URL:         registryURL,
+		}
+	}
+
+	hubURL := config.URL
+	if hubURL == "" {
+		hubURL = DockerHubURL
+	}
+
+	return DockerHub{
+		Credentials: credentials,
+		Repository:  image.GetDockerHubTagsURL(),
+		URL:         hubURL,
+	}
+}
+
+// get performs an authenticated GET request, resolving the bearer token challenge of the
+// Docker Registry token authentication specification if the server asks for it
+func get(requestURL string, credentials Credentials) (*http.Response, error) {
+	req, err := http.NewRequest("GET", requestURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if credentials.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+credentials.Token)
+	} else if credentials.Username != "" {
+		req.SetBasicAuth(credentials.Username, credentials.Password)
+	}
+
+	res, err := httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	challenge := res.Header.Get("Www-Authenticate")
+	if res.StatusCode != http.StatusUnauthorized || !strings.HasPrefix(challenge, "Bearer ") {
+		return res, nil
+	}
+	res.Body.Close()
+
+	log.WithFields(log.Fields{
+		"url":       requestURL,
+		"challenge": challenge,
+	}).Debug("Registry requires a bearer token")
+
+	token, err := requestToken(challenge, credentials)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	return httpClient.Do(req)
+}
+
+// requestToken obtains a bearer token from the realm defined by the challenge
+func requestToken(challenge string, credentials Credentials) (string, error) {
+	params := parseChallenge(strings.TrimPrefix(challenge, "Bearer "))
+
+	realm := params["realm"]
+	if realm == "" {
+		return "", fmt.Errorf("The authentication challenge has no realm: %s", challenge)
+	}
+
+	query := url.Values{}
+	if params["service"] != "" {
+		query.Set("service", params["service"])
+	}
+	if params["scope"] != "" {
+		query.Set("scope", params["scope"])
+	}
+
+	req, err := http.NewRequest("GET", realm+"?"+query.Encode(), nil)
+	if err != nil {
+		return "", err
+	}
+
+	if credentials.Username != "" {
+		req.SetBasicAuth(credentials.Username, credentials.Password)
+	}
+
+	res, err := httpClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("Could not get a token from %s: %s", realm, res.Status)
+	}
+
+	tokenResponse := struct {
+		AccessToken string `json:"access_token"`
+		Token       string `json:"token"`
+	}{}
+
+	err = json.NewDecoder(res.Body).Decode(&tokenResponse)
+	if err != nil {
+		return "", err
+	}
+
+	if tokenResponse.Token != "" {
+		return tokenResponse.Token, nil
+	}
+
+	return tokenResponse.AccessToken, nil
+}
+
+// parseChallenge parses the key="value" pairs of a WWW-Authenticate header
+func parseChallenge(challenge string) map[string]string {
+	params := map[string]string{}
+
+	for _, pair := range strings.Split(challenge, ",") {
+		kv := strings.SplitN(strings.TrimSpace(pair), "=", 2)
+		if len(kv) != 2 {
+			continue
+		}
+
+		params[kv[0]] = strings.Trim(kv[1], `"`)
+	}
+
+	return params
+}
+
+// getRegistryEndpoint returns the URL of the Docker Registry v2 API serving an image repository,
+// unless one is configured, and the name of the repository in that API. Docker Hub serves the API
+// from its own host, and its official images under the library namespace
+func getRegistryEndpoint(repository string, configuredURL string) (string, string) {
+	host, name := liferay.SplitRepository(repository)
+
+	registryURL := configuredURL
+	if host != liferay.DockerHubRegistry {
+		if registryURL == "" {
+			registryURL = "https://" + host
+		}
+
+		return registryURL, name
+	}
+
+	if registryURL == "" {
+		registryURL = DockerHubRegistryURL
+	}
+
+	if !strings.Contains(name, "/") {
+		name = "library/" + name
+	}
+
+	return registryURL, name
+}
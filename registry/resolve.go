@@ -0,0 +1,109 @@
+package registry
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// SelectorLatestGA symbolic tag resolved to the GA tag with the highest version
+const SelectorLatestGA = "latest-ga"
+
+// SelectorNewest symbolic tag resolved to the most recently pushed tag
+const SelectorNewest = "newest"
+
+var gaRegexp = regexp.MustCompile(`^ga\d+$`)
+var movingTagRegexp = regexp.MustCompile(`^\D+$`)
+var wildcardRegexp = regexp.MustCompile(`^(\d+(?:\.\d+)*)\.[xX*]$`)
+
+// IsSymbolicTag returns if the tag is a symbolic selector, as "latest-ga", "newest" or "7.2.x",
+// which must be resolved against the listing of tags
+func IsSymbolicTag(tag string) bool {
+	return tag == SelectorLatestGA || tag == SelectorNewest || wildcardRegexp.MatchString(tag)
+}
+
+// ResolveTag resolves a symbolic selector into a concrete tag of the listing:
+//   - "7.2.x" resolves to the highest version starting with 7.2
+//   - "latest-ga" resolves to the highest version of the GA tags, as 7.2.1-ga2
+//   - "newest" resolves to the most recently pushed tag
+//
+// The moving tags, as latest or master, and the pre-releases, as 7.3.0-rc1, are never resolved, so
+// they must be requested by their exact tag
+func ResolveTag(selector string, tags []Tag) (string, error) {
+	var resolved *Tag
+	var resolvedVersion Version
+
+	switch {
+	case selector == SelectorNewest:
+		for i := range tags {
+			if tags[i].LastUpdated == "" || !isReleaseTag(tags[i].Name) {
+				continue
+			}
+
+			if resolved == nil || tags[i].LastUpdated > resolved.LastUpdated {
+				resolved = &tags[i]
+			}
+		}
+
+		if resolved == nil {
+			return "", fmt.Errorf("Cannot resolve %s: the tag source does not expose the dates of the tags", selector)
+		}
+	case selector == SelectorLatestGA || wildcardRegexp.MatchString(selector):
+		var prefix Version
+		if selector != SelectorLatestGA {
+			prefix, _ = ParseVersion(wildcardRegexp.FindStringSubmatch(selector)[1])
+		}
+
+		for i := range tags {
+			version, err := ParseVersion(tags[i].Name)
+			if err != nil || version.IsPreRelease() {
+				continue
+			}
+
+			if selector == SelectorLatestGA && !gaRegexp.MatchString(version.Suffix) {
+				continue
+			} else if selector != SelectorLatestGA && !hasPrefix(version, prefix) {
+				continue
+			}
+
+			if resolved == nil || resolvedVersion.Less(version) {
+				resolved = &tags[i]
+				resolvedVersion = version
+			}
+		}
+	default:
+		return "", fmt.Errorf("%s is not a symbolic tag", selector)
+	}
+
+	if resolved == nil {
+		return "", fmt.Errorf(
+			"There is no tag matching %s. Moving tags and pre-releases must be requested by their exact tag", selector)
+	}
+
+	return resolved.Name, nil
+}
+
+// isReleaseTag returns if the tag is neither a moving tag, as latest or master, nor a pre-release
+func isReleaseTag(tag string) bool {
+	if movingTagRegexp.MatchString(tag) {
+		return false
+	}
+
+	version, err := ParseVersion(tag)
+
+	return err != nil || !version.IsPreRelease()
+}
+
+// hasPrefix returns if the numeric components of the version start with the ones of the prefix
+func hasPrefix(version Version, prefix Version) bool {
+	if len(version.Components) < len(prefix.Components) {
+		return false
+	}
+
+	for i, c := range prefix.Components {
+		if version.Components[i] != c {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,83 @@
+package registry
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var resolveTags = []Tag{
+	{Name: "7.1.3-ga4", LastUpdated: "2019-05-01T10:00:00Z"},
+	{Name: "7.2.0-ga1", LastUpdated: "2019-06-11T10:00:00Z"},
+	{Name: "7.2.1-ga2", LastUpdated: "2019-09-11T10:00:00Z"},
+	{Name: "7.2.1-rc1", LastUpdated: "2019-09-01T10:00:00Z"},
+	{Name: "7.2.2-rc1", LastUpdated: "2019-09-25T10:00:00Z"},
+	{Name: "7.3.0-m1", LastUpdated: "2019-09-20T10:00:00Z"},
+	{Name: "latest", LastUpdated: "2019-10-01T10:00:00Z"},
+}
+
+func TestIsSymbolicTag(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.True(IsSymbolicTag("latest-ga"))
+	assert.True(IsSymbolicTag("newest"))
+	assert.True(IsSymbolicTag("7.2.x"))
+	assert.True(IsSymbolicTag("7.x"))
+	assert.False(IsSymbolicTag("7.2.0-ga1"))
+	assert.False(IsSymbolicTag("latest"))
+}
+
+func TestResolveTagWildcard(t *testing.T) {
+	tag, err := ResolveTag("7.2.x", resolveTags)
+
+	assert := assert.New(t)
+	assert.Nil(err)
+	assert.Equal("7.2.1-ga2", tag)
+}
+
+func TestResolveTagLatestGA(t *testing.T) {
+	tag, err := ResolveTag("latest-ga", resolveTags)
+
+	assert := assert.New(t)
+	assert.Nil(err)
+	assert.Equal("7.2.1-ga2", tag)
+}
+
+func TestResolveTagWildcardOnlyPreReleases(t *testing.T) {
+	_, err := ResolveTag("7.3.x", resolveTags)
+
+	assert.NotNil(t, err)
+}
+
+func TestResolveTagNewest(t *testing.T) {
+	tag, err := ResolveTag("newest", resolveTags)
+
+	assert := assert.New(t)
+	assert.Nil(err)
+	assert.Equal("7.2.1-ga2", tag)
+}
+
+func TestResolveTagNewestDateTags(t *testing.T) {
+	tags := []Tag{
+		{Name: "20191001", LastUpdated: "2019-10-01T10:00:00Z"},
+		{Name: "master", LastUpdated: "2019-10-02T10:00:00Z"},
+	}
+
+	tag, err := ResolveTag("newest", tags)
+
+	assert := assert.New(t)
+	assert.Nil(err)
+	assert.Equal("20191001", tag)
+}
+
+func TestResolveTagNewestWithoutDates(t *testing.T) {
+	_, err := ResolveTag("newest", []Tag{{Name: "7.2.0-ga1"}})
+
+	assert.NotNil(t, err)
+}
+
+func TestResolveTagNoMatch(t *testing.T) {
+	_, err := ResolveTag("6.2.x", resolveTags)
+
+	assert.NotNil(t, err)
+}
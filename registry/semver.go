@@ -0,0 +1,214 @@
+package registry
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var versionRegexp = regexp.MustCompile(`^v?(\d+(?:\.\d+)*)(?:[-.]?(.*))?$`)
+
+var preReleaseRegexp = regexp.MustCompile(
+	`(?i)(^|[^a-z])(alpha|beta|m|milestone|pre|rc|snapshot)(\d|[^a-z]|$)`)
+
+// Version numeric representation of a tag, like 7.2.0-ga1 or 7.1.10.2, where the numeric
+// components are compared one by one, and the suffix (ga1) is only used to break ties
+type Version struct {
+	Components []int
+	Suffix     string
+}
+
+// ParseVersion parses a tag into a version, returning an error if the tag does not start with
+// a numeric component
+func ParseVersion(tag string) (Version, error) {
+	matches := versionRegexp.FindStringSubmatch(tag)
+	if matches == nil {
+		return Version{}, fmt.Errorf("%s is not a valid version", tag)
+	}
+
+	version := Version{Suffix: matches[2]}
+
+	for _, c := range strings.Split(matches[1], ".") {
+		n, err := strconv.Atoi(c)
+		if err != nil {
+			return Version{}, fmt.Errorf("%s is not a valid version: %v", tag, err)
+		}
+
+		version.Components = append(version.Components, n)
+	}
+
+	return version, nil
+}
+
+// Compare returns -1, 0 or 1 if the version is lower, equal or greater than the other one,
+// considering only the numeric components. Missing components are treated as zeros
+func (v Version) Compare(other Version) int {
+	size := len(v.Components)
+	if len(other.Components) > size {
+		size = len(other.Components)
+	}
+
+	for i := 0; i < size; i++ {
+		a := component(v.Components, i)
+		b := component(other.Components, i)
+
+		if a < b {
+			return -1
+		} else if a > b {
+			return 1
+		}
+	}
+
+	return 0
+}
+
+// IsPreRelease returns if the suffix of the version marks a pre-release, as 7.3.0-m1 or
+// 7.2.1-rc1
+func (v Version) IsPreRelease() bool {
+	return preReleaseRegexp.MatchString(v.Suffix)
+}
+
+// Less returns if the version is lower than the other one, using the suffix to break ties. A
+// pre-release is lower than the release with the same numeric components
+func (v Version) Less(other Version) bool {
+	c := v.Compare(other)
+	if c != 0 {
+		return c < 0
+	}
+
+	if v.IsPreRelease() != other.IsPreRelease() {
+		return v.IsPreRelease()
+	}
+
+	return naturalLess(v.Suffix, other.Suffix)
+}
+
+func component(components []int, i int) int {
+	if i < len(components) {
+		return components[i]
+	}
+
+	return 0
+}
+
+var numberRegexp = regexp.MustCompile(`\d+|\D+`)
+
+// naturalLess compares two strings, considering the numbers inside them as numbers, so that
+// ga10 is greater than ga9
+func naturalLess(a string, b string) bool {
+	chunksA := numberRegexp.FindAllString(a, -1)
+	chunksB := numberRegexp.FindAllString(b, -1)
+
+	for i := 0; i < len(chunksA) && i < len(chunksB); i++ {
+		if chunksA[i] == chunksB[i] {
+			continue
+		}
+
+		na, errA := strconv.Atoi(chunksA[i])
+		nb, errB := strconv.Atoi(chunksB[i])
+		if errA == nil && errB == nil {
+			return na < nb
+		}
+
+		return chunksA[i] < chunksB[i]
+	}
+
+	return len(chunksA) < len(chunksB)
+}
+
+type comparator struct {
+	operator string
+	version  Version
+}
+
+// Constraint a semver range, as ">=7.2.0 <7.3", where the comparators separated by spaces must
+// all be satisfied, and the groups separated by "||" are alternatives
+type Constraint struct {
+	groups [][]comparator
+}
+
+var comparatorRegexp = regexp.MustCompile(`^(>=|<=|>|<|=|!=)?\s*(.+)$`)
+
+// ParseConstraint parses a semver range
+func ParseConstraint(constraint string) (Constraint, error) {
+	c := Constraint{}
+
+	for _, group := range strings.Split(constraint, "||") {
+		comparators := []comparator{}
+
+		// allow a space between the operator and the version, as in ">= 7.2.0"
+		fields := strings.Fields(group)
+		for i := 0; i < len(fields); i++ {
+			field := fields[i]
+			if strings.Trim(field, "<>=!") == "" && i+1 < len(fields) {
+				i++
+				field = field + fields[i]
+			}
+
+			matches := comparatorRegexp.FindStringSubmatch(field)
+			if matches == nil {
+				return Constraint{}, fmt.Errorf("%s is not a valid range", constraint)
+			}
+
+			version, err := ParseVersion(matches[2])
+			if err != nil {
+				return Constraint{}, fmt.Errorf("%s is not a valid range: %v", constraint, err)
+			}
+
+			operator := matches[1]
+			if operator == "" {
+				operator = "="
+			}
+
+			comparators = append(comparators, comparator{operator: operator, version: version})
+		}
+
+		if len(comparators) == 0 {
+			return Constraint{}, fmt.Errorf("%s is not a valid range", constraint)
+		}
+
+		c.groups = append(c.groups, comparators)
+	}
+
+	return c, nil
+}
+
+// Check returns if the version satisfies the constraint
+func (c Constraint) Check(v Version) bool {
+	for _, group := range c.groups {
+		satisfied := true
+
+		for _, comp := range group {
+			if !comp.check(v) {
+				satisfied = false
+				break
+			}
+		}
+
+		if satisfied {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (comp comparator) check(v Version) bool {
+	c := v.Compare(comp.version)
+
+	switch comp.operator {
+	case ">=":
+		return c >= 0
+	case ">":
+		return c > 0
+	case "<=":
+		return c <= 0
+	case "<":
+		return c < 0
+	case "!=":
+		return c != 0
+	}
+
+	return c == 0
+}
@@ -0,0 +1,100 @@
+package registry
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseVersion(t *testing.T) {
+	assert := assert.New(t)
+
+	version, err := ParseVersion("7.2.0-ga1")
+	assert.Nil(err)
+	assert.Equal([]int{7, 2, 0}, version.Components)
+	assert.Equal("ga1", version.Suffix)
+
+	version, err = ParseVersion("7.1.10.2")
+	assert.Nil(err)
+	assert.Equal([]int{7, 1, 10, 2}, version.Components)
+	assert.Equal("", version.Suffix)
+}
+
+func TestParseVersionInvalid(t *testing.T) {
+	_, err := ParseVersion("latest")
+
+	assert.NotNil(t, err)
+}
+
+func TestVersionLess(t *testing.T) {
+	assert := assert.New(t)
+
+	v1, _ := ParseVersion("7.1.3-ga4")
+	v2, _ := ParseVersion("7.2.0-ga1")
+	assert.True(v1.Less(v2))
+	assert.False(v2.Less(v1))
+
+	ga9, _ := ParseVersion("7.0.6-ga9")
+	ga10, _ := ParseVersion("7.0.6-ga10")
+	assert.True(ga9.Less(ga10))
+
+	rc1, _ := ParseVersion("7.2.1-rc1")
+	ga2, _ := ParseVersion("7.2.1-ga2")
+	assert.True(rc1.Less(ga2))
+	assert.False(ga2.Less(rc1))
+
+	gaRc, _ := ParseVersion("7.2.1-ga2-rc1")
+	assert.True(gaRc.Less(ga2))
+}
+
+func TestVersionIsPreRelease(t *testing.T) {
+	assert := assert.New(t)
+
+	for _, tag := range []string{"7.3.0-m1", "7.2.1-rc1", "7.2.1-ga2-rc1", "7.3.0-beta2", "7.3.0-SNAPSHOT"} {
+		version, _ := ParseVersion(tag)
+		assert.True(version.IsPreRelease(), tag)
+	}
+
+	for _, tag := range []string{"7.2.1-ga2", "7.2.10-dxp-1", "7.0.10.8", "20191001"} {
+		version, _ := ParseVersion(tag)
+		assert.False(version.IsPreRelease(), tag)
+	}
+}
+
+func TestConstraintCheck(t *testing.T) {
+	assert := assert.New(t)
+
+	constraint, err := ParseConstraint(">=7.2.0 <7.3")
+	assert.Nil(err)
+
+	for tag, expected := range map[string]bool{
+		"7.1.3-ga4": false,
+		"7.2.0-ga1": true,
+		"7.2.1-ga2": true,
+		"7.3.0-ga1": false,
+	} {
+		version, _ := ParseVersion(tag)
+		assert.Equal(expected, constraint.Check(version), tag)
+	}
+}
+
+func TestConstraintCheckAlternatives(t *testing.T) {
+	assert := assert.New(t)
+
+	constraint, err := ParseConstraint("7.0.6 || >= 7.2")
+	assert.Nil(err)
+
+	v706, _ := ParseVersion("7.0.6-ga7")
+	v713, _ := ParseVersion("7.1.3-ga4")
+	v720, _ := ParseVersion("7.2.0-ga1")
+
+	assert.True(constraint.Check(v706))
+	assert.False(constraint.Check(v713))
+	assert.True(constraint.Check(v720))
+}
+
+func TestParseConstraintInvalid(t *testing.T) {
+	_, err := ParseConstraint(">=foo")
+
+	assert.NotNil(t, err)
+}
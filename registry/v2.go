@@ -0,0 +1,123 @@
+package registry
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+var linkRegexp = regexp.MustCompile(`<([^>]+)>;\s*rel="?next"?`)
+
+type tagsListResponse struct {
+	Name string
+	Tags []string
+}
+
+// Registry tag source reading tags from a Docker Registry v2 API, as Harbor or registry:2
+type Registry struct {
+	Credentials Credentials
+	Repository  string
+	URL         string
+}
+
+// GetRepository returns the repository name in the registry
+func (r Registry) GetRepository() string {
+	return r.Repository
+}
+
+// GetTags returns a page of tags from the registry. As the tags list endpoint does not support
+// page numbers, all tags are retrieved following the Link headers, and the page is calculated
+func (r Registry) GetTags(page int, size int) (TagsPage, error) {
+	names, err := r.listTags()
+	if err != nil {
+		return TagsPage{}, err
+	}
+
+	from := (page - 1) * size
+	if page < 1 || size < 1 || (from >= len(names) && len(names) > 0) {
+		return TagsPage{}, ErrPageNotFound
+	}
+
+	to := from + size
+	if to > len(names) {
+		to = len(names)
+	}
+
+	tagsPage := TagsPage{
+		Count: len(names),
+		Tags:  []Tag{},
+	}
+
+	for _, name := range names[from:to] {
+		tagsPage.Tags = append(tagsPage.Tags, Tag{Architectures: []string{}, Name: name})
+	}
+
+	if to < len(names) {
+		tagsPage.Next = fmt.Sprintf(
+			"%s/v2/%s/tags/list?n=%d&last=%s", r.URL, r.Repository, size, url.QueryEscape(names[to-1]))
+	}
+
+	return tagsPage, nil
+}
+
+// listAllTags retrieves all tags of the repository
+func (r Registry) listAllTags() ([]Tag, error) {
+	names, err := r.listTags()
+	if err != nil {
+		return nil, err
+	}
+
+	tags := []Tag{}
+	for _, name := range names {
+		tags = append(tags, Tag{Architectures: []string{}, Name: name})
+	}
+
+	return tags, nil
+}
+
+// listTags retrieves all tag names of the repository, following the pagination of the registry
+func (r Registry) listTags() ([]string, error) {
+	names := []string{}
+
+	nextURL := fmt.Sprintf("%s/v2/%s/tags/list", r.URL, r.Repository)
+
+	for nextURL != "" {
+		res, err := get(nextURL, r.Credentials)
+		if err != nil {
+			return nil, err
+		}
+
+		if res.StatusCode == http.StatusNotFound {
+			res.Body.Close()
+			return nil, fmt.Errorf("The repository %s does not exist in %s", r.Repository, r.URL)
+		}
+
+		if res.StatusCode != http.StatusOK {
+			res.Body.Close()
+			return nil, fmt.Errorf("Error getting response from the server: %s", res.Status)
+		}
+
+		tagsList := new(tagsListResponse)
+		err = json.NewDecoder(res.Body).Decode(tagsList)
+		res.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+
+		names = append(names, tagsList.Tags...)
+
+		nextURL = ""
+		matches := linkRegexp.FindStringSubmatch(res.Header.Get("Link"))
+		if len(matches) == 2 {
+			nextURL = matches[1]
+			if strings.HasPrefix(nextURL, "/") {
+				nextURL = r.URL + nextURL
+			}
+		}
+	}
+
+	return names, nil
+}
@@ -0,0 +1,152 @@
+package registry
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testToken = "my-token"
+
+// newRegistryServer returns a registry:2 stand-in which requires a bearer token obtained from
+// its own token endpoint with basic credentials, and paginates the tags list with Link headers
+func newRegistryServer(t *testing.T) *httptest.Server {
+	var server *httptest.Server
+
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/token":
+			username, password, ok := r.BasicAuth()
+			if !ok || username != "admin" || password != "secret" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+
+			fmt.Fprintf(w, `{"token": "%s"}`, testToken)
+		case "/v2/liferay/portal/tags/list":
+			if r.Header.Get("Authorization") != "Bearer "+testToken {
+				w.Header().Set(
+					"Www-Authenticate",
+					`Bearer realm="`+server.URL+`/token",service="registry",scope="repository:liferay/portal:pull"`)
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+
+			if r.URL.Query().Get("last") == "" {
+				w.Header().Set("Link", `</v2/liferay/portal/tags/list?last=7.1.3-ga4&n=2>; rel="next"`)
+				fmt.Fprint(w, `{"name": "liferay/portal", "tags": ["7.0.6-ga7", "7.1.3-ga4"]}`)
+				return
+			}
+
+			fmt.Fprint(w, `{"name": "liferay/portal", "tags": ["7.2.0-ga1"]}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+
+	return server
+}
+
+func TestRegistryGetTags(t *testing.T) {
+	server := newRegistryServer(t)
+	defer server.Close()
+
+	registry := Registry{
+		Credentials: Credentials{Username: "admin", Password: "secret"},
+		Repository:  "liferay/portal",
+		URL:         server.URL,
+	}
+
+	assert := assert.New(t)
+
+	tagsPage, err := registry.GetTags(1, 2)
+	assert.Nil(err)
+	assert.Equal(3, tagsPage.Count)
+	assert.Equal(2, len(tagsPage.Tags))
+	assert.Equal("7.0.6-ga7", tagsPage.Tags[0].Name)
+	assert.NotEmpty(tagsPage.Next)
+
+	tagsPage, err = registry.GetTags(2, 2)
+	assert.Nil(err)
+	assert.Equal(1, len(tagsPage.Tags))
+	assert.Equal("7.2.0-ga1", tagsPage.Tags[0].Name)
+	assert.Empty(tagsPage.Next)
+}
+
+func TestRegistryGetTagsPageNotFound(t *testing.T) {
+	server := newRegistryServer(t)
+	defer server.Close()
+
+	registry := Registry{
+		Credentials: Credentials{Username: "admin", Password: "secret"},
+		Repository:  "liferay/portal",
+		URL:         server.URL,
+	}
+
+	_, err := registry.GetTags(3, 2)
+
+	assert.Equal(t, ErrPageNotFound, err)
+}
+
+func TestRegistryGetTagsStaticToken(t *testing.T) {
+	server := newRegistryServer(t)
+	defer server.Close()
+
+	registry := Registry{
+		Credentials: Credentials{Token: testToken},
+		Repository:  "liferay/portal",
+		URL:         server.URL,
+	}
+
+	tagsPage, err := registry.GetTags(1, 10)
+
+	assert := assert.New(t)
+	assert.Nil(err)
+	assert.Equal(3, len(tagsPage.Tags))
+}
+
+func TestRegistryGetTagsUnauthorized(t *testing.T) {
+	server := newRegistryServer(t)
+	defer server.Close()
+
+	registry := Registry{
+		Credentials: Credentials{Username: "admin", Password: "wrong"},
+		Repository:  "liferay/portal",
+		URL:         server.URL,
+	}
+
+	_, err := registry.GetTags(1, 10)
+
+	assert.NotNil(t, err)
+}
+
+func TestGetRegistryEndpointDockerHub(t *testing.T) {
+	assert := assert.New(t)
+
+	registryURL, repository := getRegistryEndpoint("liferay/portal", "")
+	assert.Equal(DockerHubRegistryURL, registryURL)
+	assert.Equal("liferay/portal", repository)
+
+	registryURL, repository = getRegistryEndpoint("postgres", "")
+	assert.Equal(DockerHubRegistryURL, registryURL)
+	assert.Equal("library/postgres", repository)
+
+	registryURL, repository = getRegistryEndpoint("postgres", "https://mirror.mycorp.com")
+	assert.Equal("https://mirror.mycorp.com", registryURL)
+	assert.Equal("library/postgres", repository)
+}
+
+func TestGetRegistryEndpointPrivateRegistry(t *testing.T) {
+	assert := assert.New(t)
+
+	registryURL, repository := getRegistryEndpoint("harbor.mycorp.com/liferay/portal", "")
+	assert.Equal("https://harbor.mycorp.com", registryURL)
+	assert.Equal("liferay/portal", repository)
+
+	registryURL, repository = getRegistryEndpoint("localhost:5000/portal", "http://localhost:5000")
+	assert.Equal("http://localhost:5000", registryURL)
+	assert.Equal("portal", repository)
+}